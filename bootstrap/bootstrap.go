@@ -39,6 +39,8 @@ type AppConfig struct {
 	IsConsoleMode       bool
 	ConfigPath          string
 	ShutdownTimeout     time.Duration
+	// ConfigOverrides are set on top of the loaded configuration, keyed in dot notation
+	ConfigOverrides map[string]any
 }
 
 // OptFunc is a functional option for configuring AppConfig
@@ -142,6 +144,9 @@ func appWithConfig(cfg *AppConfig) *AppInstance {
 	}
 
 	config.InitializeGlobal(configData)
+	for key, value := range cfg.ConfigOverrides {
+		config.GetGlobal().Set(key, value)
+	}
 
 	secret := config.ConfigString("app.key")
 	if secret == "" {
@@ -154,14 +159,20 @@ func appWithConfig(cfg *AppConfig) *AppInstance {
 	}
 
 	app := fiber.New(*fiberCfg)
+	dbConfig := database.DefaultGormConfig()
 	if cfg.GormConfig != nil {
-		database.New(func(c *database.Config) {
-			c.GormConfig = cfg.GormConfig
+		dbConfig.GormConfig = cfg.GormConfig
+	}
+	if _, err := database.Open(dbConfig); err != nil {
+		logger.Fatal("Failed to connect to the database", map[string]any{
+			"connection": config.ConfigString("database.default"),
+			"error":      err.Error(),
 		})
-	} else {
-		database.New()
 	}
 
+	// Reads following a write in the same request stick to the primary
+	app.Use(database.StickyReads())
+
 	// Initialize file storage
 	initializeFileStorage()
 
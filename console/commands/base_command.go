@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -347,36 +348,53 @@ func (b *BaseCommand) ShowUsage(commandName, description string, usageExamples [
 	fmt.Println()
 }
 
-// generateFromStub generates a file from an internal stub template
-func (b *BaseCommand) GenerateFromStub(stubPath, targetPath string, data any) error {
+// projectStubsDir is the directory of the application that stub:publish copies the
+// stubs to. Generators use a stub found there before the one shipped with core
+const projectStubsDir = "stubs"
+
+// coreStubsDirs lists the places the core stubs are looked up, in order
+func coreStubsDirs() []string {
 	// Find the project root by looking for go.mod
 	_, currentFile, _, _ := runtime.Caller(0)
-	projectRoot := filepath.Dir(filepath.Dir(currentFile))
-	stubDir := projectRoot + "../../internal/stubs"
-	fullStubPath := filepath.Join(stubDir, stubPath)
-
-	// Try to read from core internal stubs if local doesn't exist
-	if _, err := os.Stat(fullStubPath); os.IsNotExist(err) {
-		// Try to find core stubs
-		coreStubPath := ""
-		possiblePaths := []string{
-			"../core/internal/stubs",
-			"../../core/internal/stubs",
-			"core/internal/stubs",
-		}
+	projectRoot := filepath.Dir(filepath.Dir(filepath.Dir(currentFile)))
 
-		for _, path := range possiblePaths {
-			testPath := filepath.Join(path, stubPath)
-			if _, err := os.Stat(testPath); err == nil {
-				coreStubPath = testPath
-				break
-			}
+	return []string{
+		filepath.Join(projectRoot, "internal/stubs"),
+		"./internal/stubs",
+		"../core/internal/stubs",
+		"../../core/internal/stubs",
+		"core/internal/stubs",
+	}
+}
+
+// coreStubsDir returns the directory holding the stubs shipped with core
+func coreStubsDir() (string, error) {
+	for _, dir := range coreStubsDirs() {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
 		}
+	}
+	return "", fmt.Errorf("core stubs directory not found")
+}
 
-		if coreStubPath == "" {
-			return fmt.Errorf("stub template not found: %s", stubPath)
+// resolveStub returns the path of a stub, preferring the project's published
+// copy in projectStubsDir over the core stub
+func resolveStub(stubPath string) (string, error) {
+	candidates := append([]string{projectStubsDir}, coreStubsDirs()...)
+	for _, dir := range candidates {
+		path := filepath.Join(dir, stubPath)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
 		}
-		fullStubPath = coreStubPath
+	}
+	return "", fmt.Errorf("stub template not found: %s", stubPath)
+}
+
+// GenerateFromStub generates a file from a stub template, see resolveStub
+func (b *BaseCommand) GenerateFromStub(stubPath, targetPath string, data any) error {
+	fullStubPath, err := resolveStub(stubPath)
+	if err != nil {
+		return err
 	}
 
 	stubContent, err := os.ReadFile(fullStubPath)
@@ -386,7 +404,7 @@ func (b *BaseCommand) GenerateFromStub(stubPath, targetPath string, data any) er
 
 	tmpl, err := template.New("stub").Parse(string(stubContent))
 	if err != nil {
-		return fmt.Errorf("failed to parse stub template: %v", err)
+		return fmt.Errorf("failed to parse stub template %s: %v", fullStubPath, err)
 	}
 
 	// Create target directory if it doesn't exist
@@ -394,14 +412,13 @@ func (b *BaseCommand) GenerateFromStub(stubPath, targetPath string, data any) er
 		return fmt.Errorf("failed to create target directory: %v", err)
 	}
 
-	file, err := os.Create(targetPath)
-	if err != nil {
-		return fmt.Errorf("failed to create target file: %v", err)
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, data); err != nil {
+		return fmt.Errorf("failed to execute template: %v", err)
 	}
-	defer file.Close()
 
-	if err := tmpl.Execute(file, data); err != nil {
-		return fmt.Errorf("failed to execute template: %v", err)
+	if err := os.WriteFile(targetPath, content.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to create target file: %v", err)
 	}
 
 	return nil
@@ -409,32 +426,9 @@ func (b *BaseCommand) GenerateFromStub(stubPath, targetPath string, data any) er
 
 // CopyStubToTarget copies a stub file to target location without template processing
 func (b *BaseCommand) CopyStubToTarget(stubPath, targetPath string) error {
-	// Get the stub content from embedded stubs or file system
-	stubDir := "./internal/stubs"
-	fullStubPath := filepath.Join(stubDir, stubPath)
-
-	// Try to read from core internal stubs if local doesn't exist
-	if _, err := os.Stat(fullStubPath); os.IsNotExist(err) {
-		// Try to find core stubs
-		coreStubPath := ""
-		possiblePaths := []string{
-			"../core/internal/stubs",
-			"../../core/internal/stubs",
-			"core/internal/stubs",
-		}
-
-		for _, path := range possiblePaths {
-			testPath := filepath.Join(path, stubPath)
-			if _, err := os.Stat(testPath); err == nil {
-				coreStubPath = testPath
-				break
-			}
-		}
-
-		if coreStubPath == "" {
-			return fmt.Errorf("stub file not found: %s", stubPath)
-		}
-		fullStubPath = coreStubPath
+	fullStubPath, err := resolveStub(stubPath)
+	if err != nil {
+		return err
 	}
 
 	stubContent, err := os.ReadFile(fullStubPath)
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,25 +18,75 @@ func (c *DbCreateCommand) GetSignature() string {
 }
 
 func (c *DbCreateCommand) GetDescription() string {
-	return "Create a new Go-based migration file"
+	return "Create a new Go-based migration file (--fields=\"name:type[:modifier],...\" fills a create_<table>_table migration)"
 }
 
 func (c *DbCreateCommand) Execute(args []string) error {
-	var migrationName string
+	var migrationName, spec string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--fields="):
+			spec = strings.TrimPrefix(arg, "--fields=")
+		case !strings.HasPrefix(arg, "--") && migrationName == "":
+			migrationName = arg
+		}
+	}
 
-	if len(args) == 0 {
+	if migrationName == "" {
 		migrationName = c.AskRequired("Enter migration name (e.g., create_users_table)")
-	} else {
-		migrationName = args[0]
 	}
 
 	if migrationName == "" {
 		return fmt.Errorf("migration name cannot be empty")
 	}
 
+	fields, err := ParseFields(spec)
+	if err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		return c.createTableMigration(migrationName, fields)
+	}
+
 	return c.createMigration(migrationName)
 }
 
+// createTableMigration writes a migration creating the table of a
+// create_<table>_table migration with the given fields
+func (c *DbCreateCommand) createTableMigration(name string, fields []Field) error {
+	if !strings.HasPrefix(name, "create_") {
+		return fmt.Errorf("--fields needs a create_<table>_table migration name, got %s", name)
+	}
+
+	migrationsDir := "db/migrations"
+
+	if err := os.MkdirAll(migrationsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create migrations directory: %v", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	filePath := filepath.Join(migrationsDir, fmt.Sprintf("%s_%s.go", timestamp, name))
+
+	if _, err := os.Stat(filePath); err == nil {
+		return fmt.Errorf("migration file %s already exists", filePath)
+	}
+
+	up, down := createTableSource(extractTableName(name), fields)
+	if err := c.GenerateFromStub("migrations/{{.Timestamp}}_{{.Name}}.go.stub", filePath, MigrationTemplate{
+		Timestamp: timestamp,
+		Name:      name,
+		Up:        up,
+		Down:      down,
+	}); err != nil {
+		return err
+	}
+
+	c.PrintSuccess(fmt.Sprintf("Migration created: %s", filePath))
+	c.HandleAutoImport("db/migrations", "migration")
+
+	return nil
+}
+
 func (c *DbCreateCommand) createMigration(name string) error {
 	migrationsDir := "db/migrations"
 
@@ -116,3 +168,10 @@ func extractTableName(name string) string {
 	}
 	return "example_table"
 }
+
+type MigrationTemplate struct {
+	Timestamp string
+	Name      string
+	Up        string
+	Down      string
+}
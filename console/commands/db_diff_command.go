@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galaplate/core/database"
+)
+
+type DbDiffCommand struct {
+	BaseCommand
+}
+
+func (c *DbDiffCommand) GetSignature() string {
+	return "db:diff"
+}
+
+func (c *DbDiffCommand) GetDescription() string {
+	return "Generate a migration reconciling registered GORM models with the database (--name=, --dry-run)"
+}
+
+func (c *DbDiffCommand) Execute(args []string) error {
+	name := "sync_models_schema"
+	dryRun := false
+	var filters []string
+
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--name="):
+			name = strings.TrimPrefix(arg, "--name=")
+		case arg == "--dry-run":
+			dryRun = true
+		case strings.HasPrefix(arg, "--"):
+			// Ignore flags handled by the calling command (e.g. --from-model)
+		default:
+			filters = append(filters, arg)
+		}
+	}
+
+	models, err := c.selectModels(filters)
+	if err != nil {
+		return err
+	}
+
+	// Initialize database connection
+	if _, err := database.Open(nil); err != nil {
+		return err
+	}
+
+	diff, err := database.NewSchemaDiffer().Diff(models...)
+	if err != nil {
+		return err
+	}
+
+	if diff.IsEmpty() {
+		c.PrintSuccess("Database schema is in sync with the registered models")
+		return nil
+	}
+
+	if dryRun {
+		fmt.Println("Up:")
+		fmt.Println(diff.UpSource())
+		fmt.Println()
+		fmt.Println("Down:")
+		fmt.Println(diff.DownSource())
+		return nil
+	}
+
+	return c.writeMigration(name, diff)
+}
+
+// selectModels returns the registered models matching the given struct names
+func (c *DbDiffCommand) selectModels(filters []string) ([]any, error) {
+	if len(database.ModelRegistry) == 0 {
+		return nil, fmt.Errorf("no models registered, use database.RegisterModel in your application")
+	}
+
+	if len(filters) == 0 {
+		return database.ModelRegistry, nil
+	}
+
+	var models []any
+	for _, filter := range filters {
+		found := false
+		for _, model := range database.ModelRegistry {
+			modelType := reflect.TypeOf(model)
+			for modelType.Kind() == reflect.Ptr {
+				modelType = modelType.Elem()
+			}
+			if strings.EqualFold(modelType.Name(), filter) {
+				models = append(models, model)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("model %s is not registered", filter)
+		}
+	}
+
+	return models, nil
+}
+
+func (c *DbDiffCommand) writeMigration(name string, diff *database.SchemaDiff) error {
+	migrationsDir := "db/migrations"
+
+	if err := os.MkdirAll(migrationsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create migrations directory: %v", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	filePath := filepath.Join(migrationsDir, fmt.Sprintf("%s_%s.go", timestamp, name))
+
+	if _, err := os.Stat(filePath); err == nil {
+		return fmt.Errorf("migration file %s already exists", filePath)
+	}
+
+	if err := c.GenerateFromStub("migrations/{{.Timestamp}}_{{.Name}}.go.stub", filePath, MigrationTemplate{
+		Timestamp: timestamp,
+		Name:      name,
+		Up:        diff.UpSource(),
+		Down:      diff.DownSource(),
+	}); err != nil {
+		return err
+	}
+
+	c.PrintSuccess(fmt.Sprintf("Migration created: %s", filePath))
+	c.PrintInfo("Review the generated migration before running db:up")
+
+	return nil
+}
@@ -20,7 +20,9 @@ func (c *DbDownCommand) GetDescription() string {
 
 func (c *DbDownCommand) Execute(args []string) error {
 	// Initialize database connection
-	database.New()
+	if _, err := database.Open(nil); err != nil {
+		return err
+	}
 
 	skipConfirmation := slices.Contains(args, "--force")
 
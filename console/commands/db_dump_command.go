@@ -1,15 +1,18 @@
 package commands
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/galaplate/core/config"
 	"github.com/galaplate/core/database"
-	"github.com/galaplate/core/supports"
 )
 
 type DbDumpCommand struct {
@@ -21,7 +24,7 @@ func (c *DbDumpCommand) GetSignature() string {
 }
 
 func (c *DbDumpCommand) GetDescription() string {
-	return "Dump database to SQL file"
+	return "Dump database to SQL file (--gzip, --include=, --exclude=, --exclude-data=, --batch=, --external to use mysqldump/pg_dump/sqlite3)"
 }
 
 func (c *DbDumpCommand) Execute(args []string) error {
@@ -30,29 +33,39 @@ func (c *DbDumpCommand) Execute(args []string) error {
 		return err
 	}
 
-	dbConnection := config.ConfigString("database.default")
-	dbHost := config.ConfigString(fmt.Sprintf("database.connections.%s.host", dbConnection))
-	dbPort := config.ConfigString(fmt.Sprintf("database.connections.%s.port", dbConnection))
-	dbDatabase := config.ConfigString(fmt.Sprintf("database.connections.%s.database", dbConnection))
-	dbUsername := config.ConfigString(fmt.Sprintf("database.connections.%s.username", dbConnection))
-	dbPassword := config.ConfigString(fmt.Sprintf("database.connections.%s.password", dbConnection))
-	dbDriver := supports.MapPostgres(database.GetDriver(dbConnection))
-
-	if dbDriver == "" {
-		c.PrintError("Missing DB_CONNECTION in .env file")
-		return fmt.Errorf("missing database connection type")
+	settings, err := c.loadDumpSettings()
+	if err != nil {
+		return err
 	}
+	dbDatabase := settings.Database
 
-	// SQLite validation
-	if dbDriver == "sqlite" {
-		if dbDatabase == "" {
-			dbDatabase = "database.sqlite"
-		}
-	} else {
-		// MySQL/PostgreSQL validation
-		if dbHost == "" || dbPort == "" || dbDatabase == "" {
-			c.PrintError("Missing database configuration in .env file")
-			return fmt.Errorf("missing database configuration")
+	var (
+		filename string
+		external bool
+		compress bool
+		opts     database.DumpOptions
+	)
+
+	for _, arg := range args {
+		switch {
+		case arg == "--external":
+			external = true
+		case arg == "--gzip":
+			compress = true
+		case strings.HasPrefix(arg, "--include="):
+			opts.Include = splitList(strings.TrimPrefix(arg, "--include="))
+		case strings.HasPrefix(arg, "--exclude="):
+			opts.Exclude = splitList(strings.TrimPrefix(arg, "--exclude="))
+		case strings.HasPrefix(arg, "--exclude-data="):
+			opts.ExcludeData = splitList(strings.TrimPrefix(arg, "--exclude-data="))
+		case strings.HasPrefix(arg, "--batch="):
+			batch, err := strconv.Atoi(strings.TrimPrefix(arg, "--batch="))
+			if err != nil {
+				return fmt.Errorf("invalid --batch value: %v", err)
+			}
+			opts.BatchSize = batch
+		case !strings.HasPrefix(arg, "--") && filename == "":
+			filename = arg
 		}
 	}
 
@@ -64,61 +77,23 @@ func (c *DbDumpCommand) Execute(args []string) error {
 	}
 
 	// Generate filename with timestamp
-	var filename string
-	if len(args) > 0 {
-		filename = args[0]
-		if !filepath.IsAbs(filename) && filepath.Ext(filename) != ".sql" {
+	if filename != "" {
+		if !filepath.IsAbs(filename) && filepath.Ext(filename) != ".sql" && filepath.Ext(filename) != ".gz" {
 			filename = filepath.Join(dumpsDir, filename+".sql")
 		}
 	} else {
 		timestamp := time.Now().Format("20060102_150405")
-		filename = filepath.Join(dumpsDir, fmt.Sprintf("%s_%s.sql", dbDatabase, timestamp))
+		filename = filepath.Join(dumpsDir, fmt.Sprintf("%s_%s.sql", filepath.Base(dbDatabase), timestamp))
 	}
 
-	c.PrintInfo(fmt.Sprintf("Dumping database '%s' to %s...", dbDatabase, filename))
-
-	var cmd *exec.Cmd
-	switch dbDriver {
-	case "sqlite":
-		if err := c.checkCommand("sqlite3"); err != nil {
-			return err
-		}
-		cmd = exec.Command("sqlite3", dbDatabase, ".dump")
-	case "mysql":
-		if err := c.checkCommand("mysqldump"); err != nil {
-			return err
-		}
-		cmd = exec.Command("mysqldump",
-			fmt.Sprintf("--host=%s", dbHost),
-			fmt.Sprintf("--port=%s", dbPort),
-			fmt.Sprintf("--user=%s", dbUsername),
-			fmt.Sprintf("--password=%s", dbPassword),
-			"--single-transaction",
-			"--routines",
-			"--triggers",
-			dbDatabase,
-		)
-	case "postgres":
-		if err := c.checkCommand("pg_dump"); err != nil {
-			return err
-		}
-		os.Setenv("PGPASSWORD", dbPassword)
-		cmd = exec.Command("pg_dump",
-			fmt.Sprintf("--host=%s", dbHost),
-			fmt.Sprintf("--port=%s", dbPort),
-			fmt.Sprintf("--username=%s", dbUsername),
-			"--no-password",
-			"--verbose",
-			"--clean",
-			"--no-acl",
-			"--no-owner",
-			dbDatabase,
-		)
-	default:
-		c.PrintError(fmt.Sprintf("Unsupported database driver: %s (supported: sqlite, mysql, postgres)", dbConnection))
-		return fmt.Errorf("unsupported database driver: %s", dbConnection)
+	if strings.HasSuffix(filename, ".gz") {
+		compress = true
+	} else if compress {
+		filename += ".gz"
 	}
 
+	c.PrintInfo(fmt.Sprintf("Dumping database '%s' to %s...", dbDatabase, filename))
+
 	// Create output file
 	outFile, err := os.Create(filename)
 	if err != nil {
@@ -127,10 +102,30 @@ func (c *DbDumpCommand) Execute(args []string) error {
 	}
 	defer outFile.Close()
 
-	cmd.Stdout = outFile
-	cmd.Stderr = os.Stderr
+	var out io.Writer = outFile
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(outFile)
+		out = gz
+	}
+
+	if external {
+		err = c.dumpExternal(settings, out)
+	} else {
+		// Initialize database connection
+		if _, err = database.Open(nil); err == nil {
+			err = database.NewDumper().Dump(out, opts)
+		}
+	}
 
-	if err := cmd.Run(); err != nil {
+	// Closing the gzip writer flushes the compressed tail of the file
+	if gz != nil {
+		if closeErr := gz.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to finish compressed dump: %w", closeErr)
+		}
+	}
+
+	if err != nil {
 		c.PrintError(fmt.Sprintf("Database dump failed: %v", err))
 		// Clean up failed dump file
 		os.Remove(filename)
@@ -141,6 +136,120 @@ func (c *DbDumpCommand) Execute(args []string) error {
 	return nil
 }
 
+// dumpExternal dumps through sqlite3, mysqldump or pg_dump
+func (c *DbDumpCommand) dumpExternal(settings dumpSettings, out io.Writer) error {
+	cmd, err := c.dumpCommand(settings, false)
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// splitList splits a comma separated flag value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// dumpSettings holds the connection settings used by the dump tools
+type dumpSettings struct {
+	Connection string
+	database.ConnectionParams
+}
+
+// loadDumpSettings resolves and validates the default connection settings, from
+// the same dsn, url or structured settings database.Open connects with
+func (c *DbDumpCommand) loadDumpSettings() (dumpSettings, error) {
+	dbConnection := config.ConfigString("database.default")
+	if dbConnection == "" || database.GetDriver(dbConnection) == "" {
+		c.PrintError("Missing DB_CONNECTION in .env file")
+		return dumpSettings{Connection: dbConnection}, fmt.Errorf("missing database connection type")
+	}
+
+	params, err := database.ResolveConnectionParams(dbConnection)
+	if err != nil {
+		c.PrintError(err.Error())
+		return dumpSettings{Connection: dbConnection}, err
+	}
+	settings := dumpSettings{Connection: dbConnection, ConnectionParams: params}
+
+	// MySQL/PostgreSQL validation
+	if settings.Driver != "sqlite" && ((settings.Host == "" && settings.Socket == "") || settings.Database == "") {
+		c.PrintError("Missing database configuration in .env file")
+		return settings, fmt.Errorf("missing database configuration")
+	}
+
+	return settings, nil
+}
+
+// mysqlArgs returns the connection arguments of the mysql and mysqldump clients
+func (s dumpSettings) mysqlArgs() []string {
+	args := []string{
+		fmt.Sprintf("--user=%s", s.Username),
+		fmt.Sprintf("--password=%s", s.Password),
+	}
+	if s.Socket != "" {
+		return append(args, fmt.Sprintf("--socket=%s", s.Socket))
+	}
+	return append(args,
+		fmt.Sprintf("--host=%s", s.Host),
+		fmt.Sprintf("--port=%s", s.Port),
+	)
+}
+
+// dumpCommand builds the external dump command for the driver. With schemaOnly
+// the MySQL and PostgreSQL dumps contain the table structure without data
+func (c *DbDumpCommand) dumpCommand(settings dumpSettings, schemaOnly bool) (*exec.Cmd, error) {
+	switch settings.Driver {
+	case "sqlite":
+		if err := c.checkCommand("sqlite3"); err != nil {
+			return nil, err
+		}
+		return exec.Command("sqlite3", settings.Database, ".dump"), nil
+	case "mysql":
+		if err := c.checkCommand("mysqldump"); err != nil {
+			return nil, err
+		}
+		args := append(settings.mysqlArgs(), "--single-transaction")
+		if schemaOnly {
+			args = append(args, "--no-data", "--skip-comments", "--skip-add-drop-table")
+		} else {
+			args = append(args, "--routines", "--triggers")
+		}
+		return exec.Command("mysqldump", append(args, settings.Database)...), nil
+	case "postgres":
+		if err := c.checkCommand("pg_dump"); err != nil {
+			return nil, err
+		}
+		os.Setenv("PGPASSWORD", settings.Password)
+		args := []string{
+			fmt.Sprintf("--host=%s", settings.Host),
+			fmt.Sprintf("--port=%s", settings.Port),
+			fmt.Sprintf("--username=%s", settings.Username),
+			"--no-password",
+		}
+		if schemaOnly {
+			args = append(args, "--schema-only")
+		} else {
+			args = append(args, "--verbose", "--clean")
+		}
+		args = append(args, "--no-acl", "--no-owner", settings.Database)
+		return exec.Command("pg_dump", args...), nil
+	default:
+		c.PrintError(fmt.Sprintf("Unsupported database driver: %s (supported: sqlite, mysql, postgres)", settings.Connection))
+		return nil, fmt.Errorf("unsupported database driver: %s", settings.Connection)
+	}
+}
+
 func (c *DbDumpCommand) checkCommand(command string) error {
 	_, err := exec.LookPath(command)
 	if err != nil {
@@ -151,10 +260,10 @@ func (c *DbDumpCommand) checkCommand(command string) error {
 			c.PrintInfo("  macOS: brew install sqlite3")
 			c.PrintInfo("  Ubuntu/Debian: sudo apt-get install sqlite3")
 			c.PrintInfo("  CentOS/RHEL: sudo yum install sqlite")
-		case "mysqldump":
-			c.PrintInfo("Install MySQL client tools to use mysqldump")
-		case "pg_dump":
-			c.PrintInfo("Install PostgreSQL client tools to use pg_dump")
+		case "mysqldump", "mysql":
+			c.PrintInfo(fmt.Sprintf("Install MySQL client tools to use %s", command))
+		case "pg_dump", "psql":
+			c.PrintInfo(fmt.Sprintf("Install PostgreSQL client tools to use %s", command))
 		}
 		return fmt.Errorf("%s is not installed", command)
 	}
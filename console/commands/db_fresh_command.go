@@ -20,7 +20,9 @@ func (c *DbFreshCommand) GetDescription() string {
 
 func (c *DbFreshCommand) Execute(args []string) error {
 	// Initialize database connection
-	database.New()
+	if _, err := database.Open(nil); err != nil {
+		return err
+	}
 
 	skipConfirmation := slices.Contains(args, "--force")
 
@@ -20,7 +20,9 @@ func (c *DbResetCommand) GetDescription() string {
 
 func (c *DbResetCommand) Execute(args []string) error {
 	// Initialize database connection
-	database.New()
+	if _, err := database.Open(nil); err != nil {
+		return err
+	}
 
 	skipConfirmation := slices.Contains(args, "--force")
 
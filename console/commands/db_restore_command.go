@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/galaplate/core/database"
+)
+
+type DbRestoreCommand struct {
+	BaseCommand
+}
+
+func (c *DbRestoreCommand) GetSignature() string {
+	return "db:restore"
+}
+
+func (c *DbRestoreCommand) GetDescription() string {
+	return "Restore a SQL dump, gzipped or not (--force skips confirmation, --external uses mysql/psql/sqlite3)"
+}
+
+func (c *DbRestoreCommand) Execute(args []string) error {
+	if err := c.LoadEnvVariables(); err != nil {
+		c.PrintError(fmt.Sprintf("Failed to load environment variables: %v", err))
+		return err
+	}
+
+	var filename string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			filename = arg
+			break
+		}
+	}
+	if filename == "" {
+		filename = c.AskRequired("Enter dump file to restore")
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open dump file: %v", err)
+	}
+	defer file.Close()
+
+	if !slices.Contains(args, "--force") {
+		c.PrintWarning("⚠️  DANGER: Tables in the dump will be dropped and recreated!")
+		response := c.AskText("Type 'yes' to confirm restore", "")
+		if response != "yes" {
+			c.PrintInfo("Restore cancelled")
+			return nil
+		}
+	}
+
+	c.PrintInfo(fmt.Sprintf("Restoring %s...", filename))
+
+	if slices.Contains(args, "--external") {
+		err = c.restoreExternal(file)
+	} else {
+		// Initialize database connection
+		if _, err = database.Open(nil); err == nil {
+			err = database.NewDumper().Restore(file)
+		}
+	}
+
+	if err != nil {
+		c.PrintError(fmt.Sprintf("Database restore failed: %v", err))
+		return err
+	}
+
+	c.PrintSuccess(fmt.Sprintf("Database restored from %s", filename))
+	return nil
+}
+
+// restoreExternal pipes the dump into sqlite3, mysql or psql
+func (c *DbRestoreCommand) restoreExternal(file *os.File) error {
+	dumper := &DbDumpCommand{}
+	settings, err := dumper.loadDumpSettings()
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	switch settings.Driver {
+	case "sqlite":
+		if err := dumper.checkCommand("sqlite3"); err != nil {
+			return err
+		}
+		cmd = exec.Command("sqlite3", settings.Database)
+	case "mysql":
+		if err := dumper.checkCommand("mysql"); err != nil {
+			return err
+		}
+		cmd = exec.Command("mysql", append(settings.mysqlArgs(), settings.Database)...)
+	case "postgres":
+		if err := dumper.checkCommand("psql"); err != nil {
+			return err
+		}
+		os.Setenv("PGPASSWORD", settings.Password)
+		cmd = exec.Command("psql",
+			fmt.Sprintf("--host=%s", settings.Host),
+			fmt.Sprintf("--port=%s", settings.Port),
+			fmt.Sprintf("--username=%s", settings.Username),
+			"--no-password",
+			"--set=ON_ERROR_STOP=1",
+			settings.Database,
+		)
+	default:
+		return fmt.Errorf("unsupported database driver: %s", settings.Connection)
+	}
+
+	var in io.Reader = file
+	if strings.HasSuffix(file.Name(), ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		in = gz
+	}
+
+	cmd.Stdin = in
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
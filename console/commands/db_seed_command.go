@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/galaplate/core/database"
 	"github.com/galaplate/core/database/seeders"
@@ -16,13 +17,23 @@ func (c *DbSeedCommand) GetSignature() string {
 }
 
 func (c *DbSeedCommand) GetDescription() string {
-	return "Run database seeders"
+	return "Run database seeders (--once to skip seeders that already ran)"
 }
 
 func (c *DbSeedCommand) Execute(args []string) error {
 	var seederFile string
+	once := false
 
-	if len(args) == 0 {
+	var names []string
+	for _, arg := range args {
+		if arg == "--once" {
+			once = true
+			continue
+		}
+		names = append(names, arg)
+	}
+
+	if len(names) == 0 {
 		choices := []string{"All seeders", "Specific seeder"}
 		choice := c.AskChoice("What would you like to seed?", choices, 0)
 
@@ -30,14 +41,20 @@ func (c *DbSeedCommand) Execute(args []string) error {
 			seederFile = c.AskRequired("Enter seeder filename (without .go extension)")
 		}
 	} else {
-		seederFile = args[0]
+		seederFile = strings.Join(names, ",")
 	}
 
-	c.PrintInfo("Running database seeders...")
+	seeder, err := seeders.NewDatabaseSeederE(seederFile)
+	if err != nil {
+		return err
+	}
+	seeder.Once = once
 
-	database.New()
+	c.PrintInfo("Running database seeders...")
 
-	seeder := seeders.NewDatabaseSeeder(seederFile)
+	if _, err := database.Open(nil); err != nil {
+		return err
+	}
 
 	if err := seeder.Run(database.Connect); err != nil {
 		c.PrintError(fmt.Sprintf("Seeding failed: %v", err))
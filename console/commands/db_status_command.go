@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"slices"
+
 	"github.com/galaplate/core/database"
 )
 
@@ -13,18 +15,25 @@ func (c *DbStatusCommand) GetSignature() string {
 }
 
 func (c *DbStatusCommand) GetDescription() string {
-	return "Show database migration status"
+	return "Show database migration status (--strict fails on modified, missing or out-of-order migrations)"
 }
 
 func (c *DbStatusCommand) Execute(args []string) error {
 	// Initialize database connection
-	database.New()
+	if _, err := database.Open(nil); err != nil {
+		return err
+	}
 
 	migrator := database.NewMigrator()
 
-	if err := migrator.Status(); err != nil {
+	drift, err := migrator.StatusReport()
+	if err != nil {
 		return err
 	}
 
+	if slices.Contains(args, "--strict") && drift.HasDrift() {
+		return drift
+	}
+
 	return nil
 }
@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/galaplate/core/database"
 )
 
@@ -13,15 +17,27 @@ func (c *DbUpCommand) GetSignature() string {
 }
 
 func (c *DbUpCommand) GetDescription() string {
-	return "Run pending database migrations"
+	return "Run pending database migrations (--lock-timeout=60s)"
 }
 
 func (c *DbUpCommand) Execute(args []string) error {
 	// Initialize database connection
-	database.New()
+	if _, err := database.Open(nil); err != nil {
+		return err
+	}
 
 	migrator := database.NewMigrator()
 
+	for _, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--lock-timeout="); ok {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid --lock-timeout %q: %v", value, err)
+			}
+			migrator.SetLockTimeout(timeout)
+		}
+	}
+
 	if err := migrator.Up(); err != nil {
 		return err
 	}
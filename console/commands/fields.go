@@ -0,0 +1,557 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Field is a column of a --fields spec, written as name:type[(args)][:modifier...]
+//
+//	number:string:unique,amount:decimal(10,2),due_at:date:nullable,user_id:foreign
+//
+// Modifiers are nullable, unique, index and default(value). A *_id field without a
+// type, or with an integer type, is a foreign id constrained to the plural table
+type Field struct {
+	Name     string
+	Type     string
+	Args     []int
+	Nullable bool
+	Unique   bool
+	Index    bool
+	Default  string
+}
+
+// fieldTypes maps the spec types, and their aliases, to the canonical type
+var fieldTypes = map[string]string{
+	"string":     "string",
+	"char":       "char",
+	"text":       "text",
+	"integer":    "integer",
+	"int":        "integer",
+	"bigint":     "bigint",
+	"biginteger": "bigint",
+	"smallint":   "smallint",
+	"tinyint":    "tinyint",
+	"decimal":    "decimal",
+	"float":      "float",
+	"double":     "double",
+	"boolean":    "boolean",
+	"bool":       "boolean",
+	"date":       "date",
+	"datetime":   "datetime",
+	"timestamp":  "timestamp",
+	"time":       "time",
+	"json":       "json",
+	"uuid":       "uuid",
+	"foreign":    "foreign",
+	"foreignid":  "foreign",
+}
+
+var (
+	fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	fieldTypePattern = regexp.MustCompile(`^([a-z]+)(?:\(([\d,\s]*)\))?$`)
+	camelBoundary    = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+)
+
+// ParseFields parses a comma separated --fields spec. Commas inside parentheses,
+// as in decimal(10,2), don't separate fields
+func ParseFields(spec string) ([]Field, error) {
+	var fields []Field
+	seen := map[string]bool{}
+
+	for _, part := range splitOutsideParens(spec, ',') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field, err := parseField(part)
+		if err != nil {
+			return nil, err
+		}
+		if seen[field.Name] {
+			return nil, fmt.Errorf("field %q is given twice", field.Name)
+		}
+		seen[field.Name] = true
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func parseField(spec string) (Field, error) {
+	parts := splitOutsideParens(spec, ':')
+	if len(parts) == 1 && strings.HasSuffix(strings.TrimSpace(spec), "_id") {
+		parts = append(parts, "foreign")
+	}
+	if len(parts) < 2 {
+		return Field{}, fmt.Errorf("field %q must be written as name:type", spec)
+	}
+
+	name := strings.ToLower(camelBoundary.ReplaceAllString(strings.TrimSpace(parts[0]), "${1}_${2}"))
+	if !fieldNamePattern.MatchString(name) {
+		return Field{}, fmt.Errorf("invalid field name %q", parts[0])
+	}
+	if slices.Contains([]string{"id", "created_at", "updated_at"}, name) {
+		return Field{}, fmt.Errorf("field %q is generated already", name)
+	}
+
+	matches := fieldTypePattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(parts[1])))
+	if matches == nil {
+		return Field{}, fmt.Errorf("invalid type %q for field %s", parts[1], name)
+	}
+	fieldType, ok := fieldTypes[matches[1]]
+	if !ok {
+		return Field{}, fmt.Errorf("unknown type %q for field %s", matches[1], name)
+	}
+
+	if strings.HasSuffix(name, "_id") && (fieldType == "integer" || fieldType == "bigint") && matches[2] == "" {
+		fieldType = "foreign"
+	}
+
+	field := Field{Name: name, Type: fieldType}
+	if matches[2] != "" {
+		for _, arg := range strings.Split(matches[2], ",") {
+			value, err := strconv.Atoi(strings.TrimSpace(arg))
+			if err != nil {
+				return Field{}, fmt.Errorf("invalid argument %q for field %s", arg, name)
+			}
+			field.Args = append(field.Args, value)
+		}
+	}
+
+	switch {
+	case fieldType == "decimal" && len(field.Args) == 0:
+		field.Args = []int{8, 2}
+	case fieldType == "decimal" && len(field.Args) != 2:
+		return Field{}, fmt.Errorf("field %s: decimal takes a precision and a scale, e.g. decimal(10,2)", name)
+	case (fieldType == "string" || fieldType == "char") && len(field.Args) > 1:
+		return Field{}, fmt.Errorf("field %s: %s takes a single length", name, fieldType)
+	case fieldType == "foreign" && !strings.HasSuffix(name, "_id"):
+		return Field{}, fmt.Errorf("foreign field %s must end with _id", name)
+	}
+
+	for _, modifier := range parts[2:] {
+		modifier = strings.TrimSpace(modifier)
+		switch {
+		case modifier == "nullable":
+			field.Nullable = true
+		case modifier == "unique":
+			field.Unique = true
+		case modifier == "index":
+			field.Index = true
+		case strings.HasPrefix(modifier, "default(") && strings.HasSuffix(modifier, ")"):
+			field.Default = strings.TrimSuffix(strings.TrimPrefix(modifier, "default("), ")")
+		default:
+			return Field{}, fmt.Errorf("unknown modifier %q for field %s", modifier, name)
+		}
+	}
+
+	return field, nil
+}
+
+// splitOutsideParens splits s on sep, ignoring separators inside parentheses
+func splitOutsideParens(s string, sep rune) []string {
+	var parts []string
+	depth, start := 0, 0
+
+	for i, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// length returns the length argument of string and char fields
+func (f Field) length() int {
+	if len(f.Args) > 0 {
+		return f.Args[0]
+	}
+	if f.Type == "char" {
+		return 1
+	}
+	return 255
+}
+
+// GoName returns the struct field name, e.g. due_at -> DueAt and user_id -> UserID
+func (f Field) GoName() string {
+	var name string
+	for _, word := range strings.Split(f.Name, "_") {
+		switch word {
+		case "":
+		case "id", "url", "uuid", "ip":
+			name += strings.ToUpper(word)
+		default:
+			name += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return name
+}
+
+// baseGoType returns the Go type of the field ignoring nullability
+func (f Field) baseGoType() string {
+	switch f.Type {
+	case "integer":
+		return "int"
+	case "bigint":
+		return "int64"
+	case "smallint":
+		return "int16"
+	case "tinyint":
+		return "int8"
+	case "decimal", "double":
+		return "float64"
+	case "float":
+		return "float32"
+	case "boolean":
+		return "bool"
+	case "date", "datetime", "timestamp":
+		return "time.Time"
+	case "foreign":
+		return "uint"
+	default:
+		return "string"
+	}
+}
+
+// GoType returns the model field type, a pointer when the column is nullable
+func (f Field) GoType() string {
+	if f.Nullable {
+		return "*" + f.baseGoType()
+	}
+	return f.baseGoType()
+}
+
+// UsesTime reports whether the Go type needs the time package
+func (f Field) UsesTime() bool {
+	return f.baseGoType() == "time.Time"
+}
+
+// GormTag returns the gorm struct tag of the model field
+func (f Field) GormTag() string {
+	var tags []string
+
+	switch f.Type {
+	case "string", "char":
+		tags = append(tags, fmt.Sprintf("size:%d", f.length()))
+	case "uuid":
+		tags = append(tags, "size:36")
+	case "text", "date", "time", "json":
+		tags = append(tags, "type:"+f.Type)
+	case "decimal":
+		tags = append(tags, fmt.Sprintf("type:decimal(%d,%d)", f.Args[0], f.Args[1]))
+	}
+
+	if !f.Nullable {
+		tags = append(tags, "not null")
+	}
+	if f.Unique {
+		tags = append(tags, "uniqueIndex")
+	} else if f.Index {
+		tags = append(tags, "index")
+	}
+	if f.Default != "" {
+		tags = append(tags, "default:"+f.Default)
+	}
+
+	return strings.Join(tags, ";")
+}
+
+// ModelTag returns the complete struct tag of the model field
+func (f Field) ModelTag() string {
+	if tag := f.GormTag(); tag != "" {
+		return fmt.Sprintf("`gorm:%q json:%q`", tag, f.Name)
+	}
+	return fmt.Sprintf("`json:%q`", f.Name)
+}
+
+// BlueprintCall returns the Blueprint statement creating the column
+func (f Field) BlueprintCall() string {
+	var call string
+
+	switch f.Type {
+	case "foreign":
+		call = fmt.Sprintf("table.ForeignID(%q)", f.Name)
+		if f.Nullable {
+			call += ".Nullable()"
+		} else {
+			call += ".NotNullable()"
+		}
+		if f.Unique {
+			call += ".Unique()"
+		}
+		if f.Default != "" {
+			call += fmt.Sprintf(".Default(%s)", f.defaultLiteral())
+		}
+		if f.Nullable {
+			return call + ".Constrained().NullOnDelete()"
+		}
+		return call + ".Constrained()"
+	case "string", "char":
+		method := "String"
+		if f.Type == "char" {
+			method = "Char"
+		}
+		call = fmt.Sprintf("table.%s(%q, %d)", method, f.Name, f.length())
+	case "decimal":
+		call = fmt.Sprintf("table.Decimal(%q, %d, %d)", f.Name, f.Args[0], f.Args[1])
+	default:
+		methods := map[string]string{
+			"text":      "Text",
+			"integer":   "Integer",
+			"bigint":    "BigInteger",
+			"smallint":  "SmallInt",
+			"tinyint":   "TinyInt",
+			"float":     "Float",
+			"double":    "Double",
+			"boolean":   "Boolean",
+			"date":      "Date",
+			"datetime":  "DateTime",
+			"timestamp": "Timestamp",
+			"time":      "Time",
+			"json":      "JSON",
+			"uuid":      "UUID",
+		}
+		call = fmt.Sprintf("table.%s(%q)", methods[f.Type], f.Name)
+	}
+
+	if !f.Nullable {
+		call += ".NotNullable()"
+	}
+	if f.Unique {
+		call += ".Unique()"
+	}
+	if f.Default != "" {
+		call += fmt.Sprintf(".Default(%s)", f.defaultLiteral())
+	}
+
+	return call
+}
+
+// IndexCall returns the Blueprint statement indexing the column, if any
+func (f Field) IndexCall() string {
+	if !f.Index || f.Unique {
+		return ""
+	}
+	return fmt.Sprintf("table.Index([]string{%q})", f.Name)
+}
+
+// defaultLiteral renders the default as a Go literal matching the column type
+func (f Field) defaultLiteral() string {
+	switch f.baseGoType() {
+	case "bool", "int", "int64", "int16", "int8", "uint", "float64", "float32":
+		return f.Default
+	}
+	return strconv.Quote(f.Default)
+}
+
+// validateRules returns the validator rules of the field value
+func (f Field) validateRules() []string {
+	switch f.Type {
+	case "string", "char":
+		return []string{fmt.Sprintf("max=%d", f.length())}
+	case "uuid":
+		return []string{"uuid"}
+	case "json":
+		return []string{"json"}
+	case "foreign":
+		return []string{"gt=0"}
+	}
+	return nil
+}
+
+// requiredOnCreate reports whether creating a record needs the field. Numbers and
+// booleans aren't required as their zero value is a valid value
+func (f Field) requiredOnCreate() bool {
+	if f.Nullable || f.Default != "" {
+		return false
+	}
+	switch f.baseGoType() {
+	case "string", "time.Time", "uint":
+		return true
+	}
+	return false
+}
+
+// CreateTag returns the struct tag of the field in the create DTO
+func (f Field) CreateTag() string {
+	rules := f.validateRules()
+	if f.requiredOnCreate() {
+		rules = append([]string{"required"}, rules...)
+	} else if len(rules) > 0 {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return dtoTag(f.Name, rules)
+}
+
+// UpdateTag returns the struct tag of the field in the update DTO, where every
+// field is optional
+func (f Field) UpdateTag() string {
+	rules := f.validateRules()
+	if len(rules) > 0 {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return dtoTag(f.Name, rules)
+}
+
+// dtoType returns the Go type of the field in the DTOs. Dates are decoded from
+// "2006-01-02" by supports.Date, as time.Time only accepts RFC 3339
+func (f Field) dtoType() string {
+	if f.Type == "date" {
+		return "supports.Date"
+	}
+	return f.baseGoType()
+}
+
+// CreateType returns the field type in the create DTO
+func (f Field) CreateType() string {
+	if f.Nullable {
+		return "*" + f.dtoType()
+	}
+	return f.dtoType()
+}
+
+// UpdateType returns the field type in the update DTO, a pointer so absent
+// fields are told apart from zero values
+func (f Field) UpdateType() string {
+	return "*" + f.dtoType()
+}
+
+// CreateValue returns the expression assigning the create DTO field to the model
+func (f Field) CreateValue() string {
+	value := "input." + f.GoName()
+	if f.Type != "date" {
+		return value
+	}
+	if f.Nullable {
+		return value + ".TimePtr()"
+	}
+	return value + ".Time"
+}
+
+// UpdateValue returns the expression assigning the update DTO field, known to be
+// set, to the model
+func (f Field) UpdateValue() string {
+	value := "input." + f.GoName()
+	switch {
+	case f.Type == "date" && f.Nullable:
+		return value + ".TimePtr()"
+	case f.Type == "date":
+		return value + ".Time"
+	case f.Nullable:
+		return value
+	}
+	return "*" + value
+}
+
+func dtoTag(name string, rules []string) string {
+	if len(rules) == 0 {
+		return fmt.Sprintf("`json:%q`", name)
+	}
+	return fmt.Sprintf("`json:%q validate:%q`", name, strings.Join(rules, ","))
+}
+
+// FactoryParent returns the model a required foreign id references, whose
+// factory creates the parent record, e.g. Customer for customer_id
+func (f Field) FactoryParent() string {
+	if f.Type != "foreign" || f.Nullable || f.Default != "" {
+		return ""
+	}
+	return Field{Name: strings.TrimSuffix(f.Name, "_id")}.GoName()
+}
+
+// FactoryTodo returns the reminder the factory leaves for a required foreign id
+// when the parent has no factory to wire with For yet
+func (f Field) FactoryTodo() string {
+	parent := f.FactoryParent()
+	if parent == "" {
+		return ""
+	}
+	return fmt.Sprintf("TODO: set %s, run make:factory %s and chain For(New%sFactory()) to the base factory", f.GoName(), parent, parent)
+}
+
+// FactoryValue returns the Go expression the factory fills the field with, or
+// an empty string to leave the zero value or column default
+func (f Field) FactoryValue() string {
+	if f.Nullable || f.Default != "" || f.Type == "foreign" {
+		return ""
+	}
+
+	switch f.Type {
+	case "string", "text":
+		value := fmt.Sprintf("fmt.Sprintf(\"%s %%d\", seq)", strings.ReplaceAll(f.Name, "_", " "))
+		if f.Type == "string" && f.length() < len(f.Name)+20 {
+			value = "fmt.Sprintf(\"%d\", seq)"
+		}
+		return value
+	case "char":
+		return `"a"`
+	case "uuid":
+		return `fmt.Sprintf("00000000-0000-4000-8000-%012d", seq)`
+	case "json":
+		return `"{}"`
+	case "time":
+		return `"09:00:00"`
+	case "integer", "smallint", "tinyint", "bigint", "float", "double", "decimal":
+		return f.baseGoType() + "(seq)"
+	case "boolean":
+		return "true"
+	case "date", "datetime", "timestamp":
+		return "time.Now()"
+	}
+	return ""
+}
+
+// fieldImports returns the packages the Go types of the fields need
+func fieldImports(fields []Field) []string {
+	for _, field := range fields {
+		if field.UsesTime() {
+			return []string{"time"}
+		}
+	}
+	return nil
+}
+
+// dtoImports returns the packages the DTO types of the fields need, besides
+// supports which every DTO imports
+func dtoImports(fields []Field) []string {
+	for _, field := range fields {
+		if field.UsesTime() && field.Type != "date" {
+			return []string{"time"}
+		}
+	}
+	return nil
+}
+
+// createTableSource returns the migration Up and Down bodies creating a table with
+// an id, the fields and timestamps
+func createTableSource(table string, fields []Field) (string, string) {
+	lines := []string{
+		fmt.Sprintf("\treturn schema.Create(%q, func(table *database.Blueprint) {", table),
+		"\t\ttable.ID()",
+	}
+	for _, field := range fields {
+		lines = append(lines, "\t\t"+field.BlueprintCall())
+	}
+	lines = append(lines, "\t\ttable.Timestamps()")
+	for _, field := range fields {
+		if call := field.IndexCall(); call != "" {
+			lines = append(lines, "\t\t"+call)
+		}
+	}
+	lines = append(lines, "\t})")
+
+	return strings.Join(lines, "\n"), fmt.Sprintf("\treturn schema.DropIfExists(%q)", table)
+}
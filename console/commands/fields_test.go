@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields("number:string(32):unique, amount:decimal(10,2), dueAt:date:nullable, user_id:foreign:index, paid:bool:default(false)")
+	if err != nil {
+		t.Fatalf("Failed to parse fields: %v", err)
+	}
+	if len(fields) != 5 {
+		t.Fatalf("Expected 5 fields, got %d", len(fields))
+	}
+
+	tests := []struct {
+		field     Field
+		goName    string
+		goType    string
+		gormTag   string
+		blueprint string
+		createTag string
+	}{
+		{fields[0], "Number", "string", "size:32;not null;uniqueIndex", `table.String("number", 32).NotNullable().Unique()`, "`json:\"number\" validate:\"required,max=32\"`"},
+		{fields[1], "Amount", "float64", "type:decimal(10,2);not null", `table.Decimal("amount", 10, 2).NotNullable()`, "`json:\"amount\"`"},
+		{fields[2], "DueAt", "*time.Time", "type:date", `table.Date("due_at")`, "`json:\"due_at\"`"},
+		{fields[3], "UserID", "uint", "not null;index", `table.ForeignID("user_id").NotNullable().Constrained()`, "`json:\"user_id\" validate:\"required,gt=0\"`"},
+		{fields[4], "Paid", "bool", "not null;default:false", `table.Boolean("paid").NotNullable().Default(false)`, "`json:\"paid\"`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field.Name, func(t *testing.T) {
+			if got := tt.field.GoName(); got != tt.goName {
+				t.Errorf("GoName: expected %s, got %s", tt.goName, got)
+			}
+			if got := tt.field.GoType(); got != tt.goType {
+				t.Errorf("GoType: expected %s, got %s", tt.goType, got)
+			}
+			if got := tt.field.GormTag(); got != tt.gormTag {
+				t.Errorf("GormTag: expected %s, got %s", tt.gormTag, got)
+			}
+			if got := tt.field.BlueprintCall(); got != tt.blueprint {
+				t.Errorf("BlueprintCall: expected %s, got %s", tt.blueprint, got)
+			}
+			if got := tt.field.CreateTag(); got != tt.createTag {
+				t.Errorf("CreateTag: expected %s, got %s", tt.createTag, got)
+			}
+		})
+	}
+}
+
+func TestParseFieldsForeignIDs(t *testing.T) {
+	fields, err := ParseFields("user_id,team_id:bigint:nullable,external_id:string")
+	if err != nil {
+		t.Fatalf("Failed to parse fields: %v", err)
+	}
+
+	if fields[0].Type != "foreign" || fields[0].BlueprintCall() != `table.ForeignID("user_id").NotNullable().Constrained()` {
+		t.Errorf("Expected user_id to be a foreign id, got %+v", fields[0])
+	}
+	if fields[1].Type != "foreign" || fields[1].GoType() != "*uint" {
+		t.Errorf("Expected team_id to be a nullable foreign id, got %+v", fields[1])
+	}
+	if tag := fields[1].ModelTag(); tag != "`json:\"team_id\"`" {
+		t.Errorf("Expected no gorm tag for team_id, got %s", tag)
+	}
+	if fields[2].Type != "string" {
+		t.Errorf("Expected external_id to stay a string, got %s", fields[2].Type)
+	}
+
+	if parent := fields[0].FactoryParent(); parent != "User" {
+		t.Errorf("Expected user_id to need a User factory, got %q", parent)
+	}
+	if todo := fields[0].FactoryTodo(); !strings.Contains(todo, "set UserID") || !strings.Contains(todo, "For(NewUserFactory())") {
+		t.Errorf("Expected a factory reminder for user_id, got %q", todo)
+	}
+	if todo := fields[1].FactoryTodo(); todo != "" {
+		t.Errorf("Expected no factory reminder for the nullable team_id, got %q", todo)
+	}
+
+	fields, err = ParseFields("owner_id:foreign:unique:default(1)")
+	if err != nil {
+		t.Fatalf("Failed to parse fields: %v", err)
+	}
+	if call := fields[0].BlueprintCall(); call != `table.ForeignID("owner_id").NotNullable().Unique().Default(1).Constrained()` {
+		t.Errorf("Expected the modifiers on the foreign id, got %s", call)
+	}
+}
+
+func TestFieldDateDTOs(t *testing.T) {
+	fields, err := ParseFields("due_at:date,paid_on:date:nullable,sent_at:datetime")
+	if err != nil {
+		t.Fatalf("Failed to parse fields: %v", err)
+	}
+
+	tests := []struct {
+		field       Field
+		createType  string
+		updateType  string
+		createValue string
+		updateValue string
+	}{
+		{fields[0], "supports.Date", "*supports.Date", "input.DueAt.Time", "input.DueAt.Time"},
+		{fields[1], "*supports.Date", "*supports.Date", "input.PaidOn.TimePtr()", "input.PaidOn.TimePtr()"},
+		{fields[2], "time.Time", "*time.Time", "input.SentAt", "*input.SentAt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field.Name, func(t *testing.T) {
+			if got := tt.field.CreateType(); got != tt.createType {
+				t.Errorf("CreateType: expected %s, got %s", tt.createType, got)
+			}
+			if got := tt.field.UpdateType(); got != tt.updateType {
+				t.Errorf("UpdateType: expected %s, got %s", tt.updateType, got)
+			}
+			if got := tt.field.CreateValue(); got != tt.createValue {
+				t.Errorf("CreateValue: expected %s, got %s", tt.createValue, got)
+			}
+			if got := tt.field.UpdateValue(); got != tt.updateValue {
+				t.Errorf("UpdateValue: expected %s, got %s", tt.updateValue, got)
+			}
+		})
+	}
+
+	if imports := dtoImports(fields[:2]); len(imports) != 0 {
+		t.Errorf("Expected date-only DTOs not to import time, got %v", imports)
+	}
+}
+
+func TestParseFieldsErrors(t *testing.T) {
+	for spec, message := range map[string]string{
+		"title":                 "must be written as name:type",
+		"title:money":           "unknown type",
+		"title:string:required": "unknown modifier",
+		"total:decimal(10)":     "precision and a scale",
+		"owner:foreign":         "must end with _id",
+		"id:bigint":             "generated already",
+		"a:string,a:text":       "given twice",
+		"9lives:integer":        "invalid field name",
+	} {
+		if _, err := ParseFields(spec); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("ParseFields(%q): expected an error containing %q, got %v", spec, message, err)
+		}
+	}
+}
+
+func TestCreateTableSource(t *testing.T) {
+	fields, _ := ParseFields("title:string,published_at:timestamp:nullable:index")
+	up, down := createTableSource("posts", fields)
+
+	expected := strings.Join([]string{
+		`	return schema.Create("posts", func(table *database.Blueprint) {`,
+		`		table.ID()`,
+		`		table.String("title", 255).NotNullable()`,
+		`		table.Timestamp("published_at")`,
+		`		table.Timestamps()`,
+		`		table.Index([]string{"published_at"})`,
+		`	})`,
+	}, "\n")
+	if up != expected {
+		t.Errorf("Unexpected Up source:\n%s", up)
+	}
+	if down != `	return schema.DropIfExists("posts")` {
+		t.Errorf("Unexpected Down source: %s", down)
+	}
+}
+
+func TestFactoryParents(t *testing.T) {
+	fields, err := ParseFields("customer_id:foreignId, team_id:foreignId, owner_id:foreignId:nullable, number:string")
+	if err != nil {
+		t.Fatalf("Failed to parse fields: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "customer_factory.go"), []byte("package factories\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write parent factory: %v", err)
+	}
+
+	parents := factoryParents(dir, fields)
+	if len(parents) != 1 || parents["customer_id"] != "NewCustomerFactory" {
+		t.Fatalf("Expected only customer_id wired to NewCustomerFactory, got %v", parents)
+	}
+
+	for _, field := range factoryFields(fields, parents) {
+		if field.Name == "CustomerID" {
+			t.Errorf("Expected CustomerID to be left to For, got %+v", field)
+		}
+		if field.Name == "TeamID" && !strings.Contains(field.Todo, "make:factory Team") {
+			t.Errorf("Expected a reminder for TeamID, got %+v", field)
+		}
+	}
+}
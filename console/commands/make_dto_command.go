@@ -38,14 +38,15 @@ func (c *MakeDtoCommand) Execute(args []string) error {
 		return err
 	}
 
-	return c.createDto(dtoName)
+	return c.createDto(dtoName, nil, nil)
 }
 
 func (c *MakeDtoCommand) askForDtoName() string {
 	return c.AskRequired("Enter DTO name (e.g., UserCreate, ProductUpdate)")
 }
 
-func (c *MakeDtoCommand) createDto(name string) error {
+// createDto generates a DTO, with the given fields or the commented example
+func (c *MakeDtoCommand) createDto(name string, fields []DtoField, imports []string) error {
 	dtoDir := "./pkg/dto"
 
 	if err := os.MkdirAll(dtoDir, 0755); err != nil {
@@ -71,6 +72,8 @@ func (c *MakeDtoCommand) createDto(name string) error {
 		StructName: structName,
 		ModuleName: moduleName,
 		Timestamp:  time.Now().Format("2006-01-02 15:04:05"),
+		Imports:    imports,
+		Fields:     fields,
 	}); err != nil {
 		return err
 	}
@@ -85,4 +88,13 @@ type DtoTemplate struct {
 	StructName string
 	ModuleName string
 	Timestamp  string
+	Imports    []string
+	Fields     []DtoField
+}
+
+// DtoField is a field of a generated DTO
+type DtoField struct {
+	Name string
+	Type string
+	Tag  string
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 )
@@ -38,14 +40,14 @@ func (c *MakeFactoryCommand) Execute(args []string) error {
 		return err
 	}
 
-	return c.createFactory(modelName)
+	return c.createFactory(modelName, nil)
 }
 
 func (c *MakeFactoryCommand) askForModelName() string {
 	return c.AskRequired("Enter model name (e.g., User, Product)")
 }
 
-func (c *MakeFactoryCommand) createFactory(name string) error {
+func (c *MakeFactoryCommand) createFactory(name string, fields []Field) error {
 	factoryDir := "db/factories"
 
 	if err := os.MkdirAll(factoryDir, 0755); err != nil {
@@ -68,6 +70,14 @@ func (c *MakeFactoryCommand) createFactory(name string) error {
 	factoryName := structName + "Factory"
 	factoryConstructor := "New" + factoryName
 
+	parents := factoryParents(factoryDir, fields)
+	var constructors []string
+	for _, field := range fields {
+		if constructor, ok := parents[field.Name]; ok {
+			constructors = append(constructors, constructor)
+		}
+	}
+
 	// Use internal stub template
 	if err := c.GenerateFromStub("factories/factory.go.stub", filePath, FactoryTemplate{
 		FactoryName:        factoryName,
@@ -75,6 +85,9 @@ func (c *MakeFactoryCommand) createFactory(name string) error {
 		ModelName:          structName,
 		Timestamp:          time.Now().Format("2006-01-02 15:04:05"),
 		ModuleName:         moduleName,
+		Imports:            factoryImports(fields, parents),
+		Fields:             factoryFields(fields, parents),
+		Parents:            constructors,
 	}); err != nil {
 		return err
 	}
@@ -83,6 +96,13 @@ func (c *MakeFactoryCommand) createFactory(name string) error {
 	fmt.Printf("📝 Factory struct: %s\n", factoryName)
 	fmt.Printf("📝 Model: %s\n", structName)
 
+	for _, field := range fields {
+		if _, ok := parents[field.Name]; !ok && field.FactoryParent() != "" {
+			fmt.Printf("⚠️  %s needs a %s record: run make:factory %s, then chain For(New%sFactory()) in %s\n",
+				field.Name, field.FactoryParent(), field.FactoryParent(), field.FactoryParent(), filePath)
+		}
+	}
+
 	return nil
 }
 
@@ -92,4 +112,61 @@ type FactoryTemplate struct {
 	ModelName          string
 	Timestamp          string
 	ModuleName         string
+	Imports            []string
+	Fields             []FactoryField
+	Parents            []string // Constructors of the parent factories chained with For
+}
+
+// FactoryField is a model field the generated factory fills, or leaves a Todo for
+type FactoryField struct {
+	Name  string
+	Value string
+	Todo  string
+}
+
+// factoryParents returns the constructors of the existing factories creating the
+// parents of required foreign ids, keyed by field name
+func factoryParents(factoryDir string, fields []Field) map[string]string {
+	parents := map[string]string{}
+	for _, field := range fields {
+		parent := field.FactoryParent()
+		if parent == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(factoryDir, strings.ToLower(parent)+"_factory.go")); err == nil {
+			parents[field.Name] = "New" + parent + "Factory"
+		}
+	}
+	return parents
+}
+
+// factoryFields returns the fields with a factory value or reminder. Fields set
+// by a parent factory are left to For
+func factoryFields(fields []Field, parents map[string]string) []FactoryField {
+	var factoryFields []FactoryField
+	for _, field := range fields {
+		if _, ok := parents[field.Name]; ok {
+			continue
+		}
+		if value := field.FactoryValue(); value != "" {
+			factoryFields = append(factoryFields, FactoryField{Name: field.GoName(), Value: value})
+		} else if todo := field.FactoryTodo(); todo != "" {
+			factoryFields = append(factoryFields, FactoryField{Name: field.GoName(), Todo: todo})
+		}
+	}
+	return factoryFields
+}
+
+// factoryImports returns the packages the factory values need
+func factoryImports(fields []Field, parents map[string]string) []string {
+	var imports []string
+	for _, field := range factoryFields(fields, parents) {
+		for _, pkg := range []string{"fmt", "time"} {
+			if strings.Contains(field.Value, pkg+".") && !slices.Contains(imports, pkg) {
+				imports = append(imports, pkg)
+			}
+		}
+	}
+	sort.Strings(imports)
+	return imports
 }
@@ -262,8 +262,3 @@ type JobTemplate struct {
 	MaxAttempts  int
 	RetryMinutes int
 }
-
-type MigrationTemplate struct {
-	Timestamp string
-	Name      string
-}
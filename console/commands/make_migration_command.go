@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"slices"
+	"strings"
+)
+
+type MakeMigrationCommand struct {
+	BaseCommand
+}
+
+func (c *MakeMigrationCommand) GetSignature() string {
+	return "make:migration"
+}
+
+func (c *MakeMigrationCommand) GetDescription() string {
+	return "Create a new migration (use --from-model to generate it from registered GORM models)"
+}
+
+func (c *MakeMigrationCommand) Execute(args []string) error {
+	if slices.Contains(args, "--from-model") {
+		// make:migration <name> --from-model [Model...] maps the name to db:diff --name
+		diffArgs := []string{}
+		named := false
+		for _, arg := range args {
+			if !named && !strings.HasPrefix(arg, "--") {
+				diffArgs = append(diffArgs, "--name="+arg)
+				named = true
+				continue
+			}
+			diffArgs = append(diffArgs, arg)
+		}
+		return (&DbDiffCommand{}).Execute(diffArgs)
+	}
+
+	return (&DbCreateCommand{}).Execute(args)
+}
@@ -17,16 +17,22 @@ func (c *MakeModelCommand) GetSignature() string {
 }
 
 func (c *MakeModelCommand) GetDescription() string {
-	return "Create a new model"
+	return "Create a new model (--fields=\"name:type[:modifier],...\" adds the fields with GORM and JSON tags)"
 }
 
 func (c *MakeModelCommand) Execute(args []string) error {
-	var modelName string
+	var modelName, spec string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--fields="):
+			spec = strings.TrimPrefix(arg, "--fields=")
+		case !strings.HasPrefix(arg, "--") && modelName == "":
+			modelName = arg
+		}
+	}
 
-	if len(args) == 0 {
+	if modelName == "" {
 		modelName = c.askForModelName()
-	} else {
-		modelName = args[0]
 	}
 
 	if modelName == "" {
@@ -38,14 +44,19 @@ func (c *MakeModelCommand) Execute(args []string) error {
 		return err
 	}
 
-	return c.createModel(modelName)
+	fields, err := ParseFields(spec)
+	if err != nil {
+		return err
+	}
+
+	return c.createModel(modelName, fields)
 }
 
 func (c *MakeModelCommand) askForModelName() string {
 	return c.AskRequired("Enter model name (e.g., User, Product)")
 }
 
-func (c *MakeModelCommand) createModel(name string) error {
+func (c *MakeModelCommand) createModel(name string, fields []Field) error {
 	modelDir := "./pkg/models"
 
 	if err := os.MkdirAll(modelDir, 0755); err != nil {
@@ -65,6 +76,7 @@ func (c *MakeModelCommand) createModel(name string) error {
 	if err := c.GenerateFromStub("models/model.go.stub", filePath, ModelTemplate{
 		StructName: structName,
 		Timestamp:  time.Now().Format("2006-01-02 15:04:05"),
+		Fields:     fields,
 	}); err != nil {
 		return err
 	}
@@ -78,4 +90,5 @@ func (c *MakeModelCommand) createModel(name string) error {
 type ModelTemplate struct {
 	StructName string
 	Timestamp  string
+	Fields     []Field
 }
@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galaplate/core/supports"
+)
+
+type MakeResourceCommand struct {
+	BaseCommand
+}
+
+func (c *MakeResourceCommand) GetSignature() string {
+	return "make:resource"
+}
+
+func (c *MakeResourceCommand) GetDescription() string {
+	return "Create a model, migration, DTOs, policy, factory and CRUD handler (--fields=\"name:type[:modifier],...\")"
+}
+
+func (c *MakeResourceCommand) Execute(args []string) error {
+	var name, spec string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--fields="):
+			spec = strings.TrimPrefix(arg, "--fields=")
+		case arg == "--help" || arg == "-h":
+			c.ShowUsage(c.GetSignature(), c.GetDescription(), []string{
+				`make:resource Invoice --fields="number:string:unique,amount:decimal(10,2),due_at:date"`,
+				"",
+				"Types:     string(len) char(len) text integer bigint smallint tinyint decimal(p,s) float double",
+				"           boolean date datetime timestamp time json uuid foreign",
+				"Modifiers: nullable unique index default(value)",
+			})
+			return nil
+		case !strings.HasPrefix(arg, "--") && name == "":
+			name = arg
+		}
+	}
+
+	if name == "" {
+		name = c.AskRequired("Enter resource name (e.g., Invoice, Product)")
+	}
+	if err := c.ValidateName(name, "Resource"); err != nil {
+		return err
+	}
+
+	if spec == "" {
+		spec = c.AskText("Enter fields (e.g., title:string,price:decimal(10,2))", "")
+	}
+	fields, err := ParseFields(spec)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("a resource needs at least one field, see make:resource --help")
+	}
+
+	return c.createResource(name, fields)
+}
+
+func (c *MakeResourceCommand) createResource(name string, fields []Field) error {
+	moduleName, err := c.GetModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to get module name: %v", err)
+	}
+
+	structName := c.FormatStructName(name)
+	lowerName := strings.ToLower(name)
+	snakeName := strings.ToLower(camelBoundary.ReplaceAllString(structName, "${1}_${2}"))
+	tableName := supports.Pluralize(snakeName)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	migrationName := "create_" + tableName + "_table"
+
+	paths := map[string]string{
+		"model":     filepath.Join("pkg/models", lowerName+".go"),
+		"migration": filepath.Join("db/migrations", fmt.Sprintf("%s_%s.go", timestamp, migrationName)),
+		"create":    filepath.Join("pkg/dto", lowerName+"create.go"),
+		"update":    filepath.Join("pkg/dto", lowerName+"update.go"),
+		"policy":    filepath.Join("pkg/policies", lowerName+"_policy.go"),
+		"factory":   filepath.Join("db/factories", lowerName+"_factory.go"),
+		"handler":   filepath.Join("pkg/handlers", snakeName+"_handler.go"),
+	}
+
+	// Check every file first so a clash doesn't leave half a resource behind
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("file %s already exists", path)
+		}
+	}
+
+	model := &MakeModelCommand{BaseCommand: c.BaseCommand}
+	if err := model.createModel(structName, fields); err != nil {
+		return err
+	}
+
+	up, down := createTableSource(tableName, fields)
+	if err := c.GenerateFromStub("migrations/{{.Timestamp}}_{{.Name}}.go.stub", paths["migration"], MigrationTemplate{
+		Timestamp: timestamp,
+		Name:      migrationName,
+		Up:        up,
+		Down:      down,
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Migration created successfully: %s\n", paths["migration"])
+
+	dtos := &MakeDtoCommand{BaseCommand: c.BaseCommand}
+	var createFields, updateFields []DtoField
+	for _, field := range fields {
+		createFields = append(createFields, DtoField{Name: field.GoName(), Type: field.CreateType(), Tag: field.CreateTag()})
+		updateFields = append(updateFields, DtoField{Name: field.GoName(), Type: field.UpdateType(), Tag: field.UpdateTag()})
+	}
+	if err := dtos.createDto(structName+"Create", createFields, dtoImports(fields)); err != nil {
+		return err
+	}
+	if err := dtos.createDto(structName+"Update", updateFields, dtoImports(fields)); err != nil {
+		return err
+	}
+
+	if err := c.GenerateFromStub("policies/policy.go.stub", paths["policy"], PolicyTemplate{
+		StructName: structName,
+		PolicyName: lowerName,
+		Timestamp:  time.Now().Format("2006-01-02 15:04:05"),
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Policy created successfully: %s\n", paths["policy"])
+
+	factory := &MakeFactoryCommand{BaseCommand: c.BaseCommand}
+	if err := factory.createFactory(structName, fields); err != nil {
+		return err
+	}
+
+	if err := c.GenerateFromStub("resources/handler.go.stub", paths["handler"], ResourceTemplate{
+		ModuleName: moduleName,
+		StructName: structName,
+		VarName:    strings.ReplaceAll(snakeName, "_", " "),
+		TableName:  tableName,
+		RoutePath:  strings.ReplaceAll(tableName, "_", "-"),
+		PolicyName: lowerName,
+		Timestamp:  time.Now().Format("2006-01-02 15:04:05"),
+		Fields:     fields,
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Handler created successfully: %s\n", paths["handler"])
+
+	c.HandleAutoImport("db/migrations", "migration")
+	c.HandleAutoImport("pkg/policies", "policy")
+
+	fmt.Println()
+	c.PrintInfo("Next steps:")
+	fmt.Println("1. Run the migration:")
+	fmt.Println("   go run main.go console db:up")
+	fmt.Println("2. Mount the routes:")
+	fmt.Printf("   handlers.Register%sRoutes(app.Group(\"/api\"))\n", structName)
+	fmt.Printf("3. Implement the %s policy in %s\n", lowerName, paths["policy"])
+
+	return nil
+}
+
+type ResourceTemplate struct {
+	ModuleName string
+	StructName string
+	VarName    string
+	TableName  string
+	RoutePath  string
+	PolicyName string
+	Timestamp  string
+	Fields     []Field
+}
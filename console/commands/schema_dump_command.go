@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/galaplate/core/database"
+)
+
+type SchemaDumpCommand struct {
+	BaseCommand
+}
+
+func (c *SchemaDumpCommand) GetSignature() string {
+	return "schema:dump"
+}
+
+func (c *SchemaDumpCommand) GetDescription() string {
+	return "Dump the database schema and migrations table to db/schema (--prune deletes the squashed migration files)"
+}
+
+func (c *SchemaDumpCommand) Execute(args []string) error {
+	if err := c.LoadEnvVariables(); err != nil {
+		c.PrintError(fmt.Sprintf("Failed to load environment variables: %v", err))
+		return err
+	}
+
+	dumper := &DbDumpCommand{}
+	settings, err := dumper.loadDumpSettings()
+	if err != nil {
+		return err
+	}
+
+	// Initialize database connection
+	if _, err := database.Open(nil); err != nil {
+		return err
+	}
+
+	migrator := database.NewMigrator()
+
+	var schema []byte
+	if settings.Driver == "sqlite" {
+		// Dumped natively to leave out the migrator's lock table
+		sql, err := migrator.SQLiteSchema()
+		if err != nil {
+			return fmt.Errorf("failed to read sqlite schema: %v", err)
+		}
+		schema = []byte(sql)
+	} else {
+		cmd, err := dumper.dumpCommand(settings, true)
+		if err != nil {
+			return err
+		}
+		var stdout bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			c.PrintError(fmt.Sprintf("Schema dump failed: %v", err))
+			return err
+		}
+		schema = stdout.Bytes()
+	}
+
+	// Pruned migrations are squashed in the dump too, so databases loading it
+	// never try to roll them back
+	prune := slices.Contains(args, "--prune")
+	rows, err := migrator.MigrationRowsSQL(prune)
+	if err != nil {
+		return fmt.Errorf("failed to read migrations table: %v", err)
+	}
+
+	path := database.SchemaDumpPath(settings.Driver)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create schema directory: %v", err)
+	}
+
+	content := append(schema, []byte("\n"+rows)...)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write schema dump: %v", err)
+	}
+
+	c.PrintSuccess(fmt.Sprintf("Schema dumped to %s", path))
+
+	if prune {
+		return c.prune(migrator)
+	}
+
+	return nil
+}
+
+// prune deletes the migration files of every ran migration, now part of the dump
+func (c *SchemaDumpCommand) prune(migrator *database.Migrator) error {
+	ran, err := migrator.GetRanMigrations()
+	if err != nil {
+		return err
+	}
+
+	pruned := 0
+	for _, name := range ran {
+		file := filepath.Join("db", "migrations", name+".go")
+		if err := os.Remove(file); err == nil {
+			pruned++
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to prune %s: %v", file, err)
+		}
+	}
+
+	if err := migrator.MarkSquashed(); err != nil {
+		return fmt.Errorf("failed to mark migrations as squashed: %v", err)
+	}
+
+	c.PrintSuccess(fmt.Sprintf("Pruned %d migration file(s)", pruned))
+	return nil
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/galaplate/core/config"
+	"github.com/galaplate/core/database"
+	"github.com/galaplate/core/supports"
+)
+
+type SchemaLoadCommand struct {
+	BaseCommand
+}
+
+func (c *SchemaLoadCommand) GetSignature() string {
+	return "schema:load"
+}
+
+func (c *SchemaLoadCommand) GetDescription() string {
+	return "Load the schema dump from db/schema into the database"
+}
+
+func (c *SchemaLoadCommand) Execute(args []string) error {
+	// Initialize database connection
+	if _, err := database.Open(nil); err != nil {
+		return err
+	}
+
+	path := database.SchemaDumpPath(supports.MapPostgres(database.GetDriver(config.ConfigString("database.default"))))
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	if err := database.NewMigrator().LoadSchemaDump(path); err != nil {
+		return err
+	}
+
+	c.PrintSuccess(fmt.Sprintf("Schema loaded from %s", path))
+	return nil
+}
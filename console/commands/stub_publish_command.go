@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type StubPublishCommand struct {
+	BaseCommand
+}
+
+func (c *StubPublishCommand) GetSignature() string {
+	return "stub:publish"
+}
+
+func (c *StubPublishCommand) GetDescription() string {
+	return "Copy the generator stubs to stubs/ for customization (stub:publish [models jobs ...] [--force])"
+}
+
+func (c *StubPublishCommand) Execute(args []string) error {
+	force := slices.Contains(args, "--force")
+
+	var groups []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			groups = append(groups, strings.TrimSuffix(arg, "/"))
+		}
+	}
+
+	coreDir, err := coreStubsDir()
+	if err != nil {
+		return err
+	}
+
+	published, skipped := 0, 0
+	err = filepath.WalkDir(coreDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		relPath, err := filepath.Rel(coreDir, path)
+		if err != nil {
+			return err
+		}
+		group := strings.Split(filepath.ToSlash(relPath), "/")[0]
+		if len(groups) > 0 && !slices.Contains(groups, group) {
+			return nil
+		}
+
+		targetPath := filepath.Join(projectStubsDir, relPath)
+		if _, err := os.Stat(targetPath); err == nil && !force {
+			c.PrintWarning(fmt.Sprintf("Skipped %s, it already exists (use --force to overwrite)", targetPath))
+			skipped++
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read stub %s: %v", path, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return fmt.Errorf("failed to create stubs directory: %v", err)
+		}
+		if err := os.WriteFile(targetPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write stub %s: %v", targetPath, err)
+		}
+
+		fmt.Printf("📄 Published %s\n", targetPath)
+		published++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if published == 0 && skipped == 0 {
+		return fmt.Errorf("no stubs found for %s", strings.Join(groups, ", "))
+	}
+
+	if published == 0 {
+		c.PrintInfo("All stubs are already published")
+		return nil
+	}
+
+	c.PrintSuccess(fmt.Sprintf("Published %d stubs to %s/, make:* commands now use them", published, projectStubsDir))
+	return nil
+}
@@ -38,6 +38,9 @@ func (k *Kernel) registerDefaultCommands() {
 	k.Register(&commands.MakeCronCommand{})
 	k.Register(&commands.MakeSeederCommand{})
 	k.Register(&commands.MakeFactoryCommand{})
+	k.Register(&commands.MakeMigrationCommand{})
+	k.Register(&commands.MakeResourceCommand{})
+	k.Register(&commands.StubPublishCommand{})
 
 	// Database commands
 	k.Register(&commands.DbCreateCommand{})
@@ -48,6 +51,10 @@ func (k *Kernel) registerDefaultCommands() {
 	k.Register(&commands.DbFreshCommand{})
 	k.Register(&commands.DbSeedCommand{})
 	k.Register(&commands.DbDumpCommand{})
+	k.Register(&commands.DbRestoreCommand{})
+	k.Register(&commands.DbDiffCommand{})
+	k.Register(&commands.SchemaDumpCommand{})
+	k.Register(&commands.SchemaLoadCommand{})
 
 	// Policy management command
 	k.Register(commands.NewPolicyCommand())
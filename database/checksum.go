@@ -0,0 +1,112 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// DriftReport lists ran migrations that no longer match the registered ones
+type DriftReport struct {
+	// Modified migrations ran with a different checksum than their current SQL
+	Modified []string
+
+	// Missing migrations are recorded in the migrations table but not registered.
+	// Migrations squashed into a schema dump are not reported
+	Missing []string
+
+	// OutOfOrder migrations are pending but older than the latest ran migration
+	OutOfOrder []string
+}
+
+// HasDrift reports whether any drift was detected
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Modified) > 0 || len(r.Missing) > 0 || len(r.OutOfOrder) > 0
+}
+
+// Error summarizes the drift as an error message
+func (r *DriftReport) Error() string {
+	var parts []string
+	if len(r.Modified) > 0 {
+		parts = append(parts, fmt.Sprintf("modified: %s", strings.Join(r.Modified, ", ")))
+	}
+	if len(r.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing: %s", strings.Join(r.Missing, ", ")))
+	}
+	if len(r.OutOfOrder) > 0 {
+		parts = append(parts, fmt.Sprintf("out of order: %s", strings.Join(r.OutOfOrder, ", ")))
+	}
+	return "migration drift detected (" + strings.Join(parts, "; ") + ")"
+}
+
+// Checksum returns a SHA-256 of the SQL generated by a dry-run of the migration's Up
+func (m *Migrator) Checksum(migration Migration) (string, error) {
+	pretendSchema, err := m.connectionFor(migration)
+	if err != nil {
+		return "", err
+	}
+	pretendSchema.pretend = true
+
+	if err := migration.Up(pretendSchema); err != nil {
+		return "", fmt.Errorf("failed to generate SQL for migration %s: %w", migration.GetName(), err)
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(pretendSchema.pretended, "\n")))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Drift compares the migrations table with the registered migrations.
+// Records without a checksum (ran before checksums were recorded) are not
+// checked for modification
+func (m *Migrator) Drift() (*DriftReport, error) {
+	if err := m.CreateMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	var records []MigrationInfo
+	if err := m.db.Table("migrations").Order("batch ASC, migration ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{}
+	ranMap := make(map[string]bool)
+	var latestRan int64
+
+	for _, record := range records {
+		ranMap[record.Migration] = true
+
+		migration := m.registry.GetMigrationByName(record.Migration)
+		if migration == nil {
+			// Squashed migrations live on in the schema dump
+			if record.Batch != squashedBatch {
+				report.Missing = append(report.Missing, record.Migration)
+			}
+			continue
+		}
+
+		if migration.GetTimestamp() > latestRan {
+			latestRan = migration.GetTimestamp()
+		}
+
+		if record.Checksum == "" {
+			continue
+		}
+
+		checksum, err := m.Checksum(migration)
+		if err != nil {
+			return nil, err
+		}
+		if checksum != record.Checksum {
+			report.Modified = append(report.Modified, record.Migration)
+		}
+	}
+
+	for _, migration := range m.registry.GetMigrations() {
+		if !ranMap[migration.GetName()] && migration.GetTimestamp() < latestRan {
+			report.OutOfOrder = append(report.OutOfOrder, migration.GetName())
+		}
+	}
+
+	return report, nil
+}
@@ -3,23 +3,23 @@ package database
 import (
 	"fmt"
 	"log"
-	"os"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/galaplate/core/config"
 	"github.com/galaplate/core/supports"
-	"gorm.io/driver/mysql"
-	"gorm.io/driver/postgres"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 var Connect *gorm.DB
 
 type Config struct {
 	GormConfig *gorm.Config
+
+	// Retries is how many times Open retries a failed connection, waiting
+	// RetryDelay first and doubling the delay after each attempt
+	Retries    int
+	RetryDelay time.Duration
 }
 
 func ConnectDB() {
@@ -39,94 +39,131 @@ func New(opts ...OptFunc) {
 	ConnectWithConfig(cfg)
 }
 
-// DefaultGormConfig returns default GORM configuration
+// DefaultGormConfig returns default GORM configuration, with the logger
+// configured from database.log
 func DefaultGormConfig() *Config {
-	var logLevel logger.LogLevel
-	logLevelStr := "warn" // default
-
-	switch strings.ToLower(logLevelStr) {
-	case "silent":
-		logLevel = logger.Silent
-	case "error":
-		logLevel = logger.Error
-	case "warn":
-		logLevel = logger.Warn
-	case "info":
-		logLevel = logger.Info
-	default:
-		logLevel = logger.Warn
-	}
-
 	return &Config{
 		GormConfig: &gorm.Config{
-			Logger: logger.New(
-				log.New(os.Stdout, "\r\n", log.LstdFlags),
-				logger.Config{
-					SlowThreshold:             time.Second,
-					LogLevel:                  logLevel,
-					IgnoreRecordNotFoundError: true,
-					ParameterizedQueries:      true,
-					Colorful:                  true,
-				},
-			),
+			Logger:                                   loggerFromConfig(),
 			DisableForeignKeyConstraintWhenMigrating: true,
 		},
+		Retries:    connectRetries(),
+		RetryDelay: connectRetryDelay(),
 	}
 }
+
+// ConnectWithConfig connects to the default connection and panics on failure,
+// use Open to handle the error instead
 func ConnectWithConfig(cfg *Config) {
-	var err error
-	var db *gorm.DB
-
-	// Use provided config or fall back to environment variables
-	var dbConn, host, port, username, password, database, driver string
-
-	dbConn = config.ConfigString("database.default")
-
-	host = config.ConfigString(fmt.Sprintf("database.connections.%s.host", dbConn))
-	port = config.ConfigString(fmt.Sprintf("database.connections.%s.port", dbConn))
-	username = config.ConfigString(fmt.Sprintf("database.connections.%s.username", dbConn))
-	password = config.ConfigString(fmt.Sprintf("database.connections.%s.password", dbConn))
-	database = config.ConfigString(fmt.Sprintf("database.connections.%s.database", dbConn))
-	driver = supports.MapPostgres(GetDriver(dbConn))
-
-	switch driver {
-	case "postgres":
-		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-			host, port, username, password, database,
-		)
-
-		db, err = gorm.Open(
-			postgres.Open(dsn),
-			cfg.GormConfig,
-		)
-
-	case "mysql":
-		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-			username, password, host, port, database,
-		)
-
-		db, err = gorm.Open(
-			mysql.Open(dsn),
-			cfg.GormConfig,
-		)
-
-	case "sqlite":
-		dsn := database
-		if dsn == "" {
-			dsn = "db/database.sqlite"
+	if _, err := Open(cfg); err != nil {
+		log.Panic(err.Error())
+	}
+}
+
+var (
+	connections      = map[string]*gorm.DB{}
+	connectionConfig *Config
+	connectionsMu    sync.Mutex
+)
+
+// Connection returns the named connection like OpenConnection and panics when it
+// can't be opened. It is kept for backward compatibility, prefer OpenConnection
+//
+//	reporting := database.Connection("reporting")
+func Connection(name string) *gorm.DB {
+	db, err := OpenConnection(name)
+	if err != nil {
+		log.Panic(err.Error())
+	}
+	return db
+}
+
+// OpenConnection returns the named connection from database.connections, opening
+// it on first use with the configuration passed to New or Open. An empty name
+// returns the default connection
+//
+//	reporting, err := database.OpenConnection("reporting")
+func OpenConnection(name string) (*gorm.DB, error) {
+	if name == "" || name == config.ConfigString("database.default") {
+		if Connect != nil {
+			return Connect, nil
 		}
+		name = config.ConfigString("database.default")
+	}
 
-		db, err = gorm.Open(
-			sqlite.Open(dsn),
-			cfg.GormConfig,
-		)
-	default:
-		log.Panic("Unsupported database type", driver)
+	connectionsMu.Lock()
+	db, ok := connections[name]
+	cfg := connectionConfig
+	connectionsMu.Unlock()
+
+	if ok {
+		return db, nil
+	}
+	if cfg == nil {
+		cfg = DefaultGormConfig()
 	}
 
+	// Open outside the lock so retries don't block callers of other connections
+	db, err := openWithRetry(name, cfg)
 	if err != nil {
-		log.Panic(err.Error())
-		fmt.Println("Failed to connect to the database")
+		return nil, err
+	}
+
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+
+	// Another caller may have opened it meanwhile, keep the registered one
+	if existing, ok := connections[name]; ok {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		return existing, nil
+	}
+	connections[name] = db
+
+	return db, nil
+}
+
+// SetConnection registers an already opened connection under a name
+func SetConnection(name string, db *gorm.DB) {
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+
+	connections[name] = db
+}
+
+// openConnection opens the connection configured under database.connections.<dbConn>.
+// When the connection lists read or write hosts, queries are routed by a dbresolver
+func openConnection(dbConn string, cfg *Config) (*gorm.DB, error) {
+	driver := supports.MapPostgres(GetDriver(dbConn))
+
+	writes, err := connectionDialectors(dbConn, driver, "write")
+	if err != nil {
+		return nil, configError{err}
+	}
+	reads, err := connectionDialectors(dbConn, driver, "read")
+	if err != nil {
+		return nil, configError{err}
+	}
+
+	primary := writes
+	if len(primary) == 0 {
+		dialector, err := dialector(dbConn, driver, baseConnectionSettings(dbConn))
+		if err != nil {
+			return nil, configError{err}
+		}
+		primary = []gorm.Dialector{dialector}
+	}
+
+	db, err := gorm.Open(primary[0], cfg.GormConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the %s database: %w", dbConn, err)
+	}
+
+	if len(reads) > 0 || len(writes) > 1 {
+		if err := useResolver(db, primary, reads); err != nil {
+			return nil, fmt.Errorf("failed to connect to the %s replicas: %w", dbConn, err)
+		}
 	}
 
 	// Apply connection pool settings from config
@@ -141,9 +178,68 @@ func ConnectWithConfig(cfg *Config) {
 		if maxIdle > 0 {
 			sqlDB.SetMaxIdleConns(maxIdle)
 		}
+
+		settings := baseConnectionSettings(dbConn)
+		if lifetime := settings.duration("conn_max_lifetime"); lifetime > 0 {
+			sqlDB.SetConnMaxLifetime(lifetime)
+		}
+		if idleTime := settings.duration("conn_max_idle_time"); idleTime > 0 {
+			sqlDB.SetConnMaxIdleTime(idleTime)
+		}
+	}
+
+	return db, nil
+}
+
+// baseConnectionSettings reads the scalar settings of database.connections.<dbConn>
+func baseConnectionSettings(dbConn string) connectionSettings {
+	section, _ := config.Config(fmt.Sprintf("database.connections.%s", dbConn)).(map[string]any)
+	return connectionSettings{}.merge(section)
+}
+
+// connectionDialectors builds a dialector per host listed under the read or write
+// section of a connection. Settings missing from the section are inherited from
+// the connection, and host (database for SQLite) may be a single value or a list
+//
+//	read:
+//	  host: [10.0.0.2, 10.0.0.3]
+//	write:
+//	  host: 10.0.0.1
+func connectionDialectors(dbConn, driver, role string) ([]gorm.Dialector, error) {
+	section, ok := config.Config(fmt.Sprintf("database.connections.%s.%s", dbConn, role)).(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+
+	base := baseConnectionSettings(dbConn).merge(section)
+
+	hostKey := "host"
+	if driver == "sqlite" {
+		hostKey = "database"
+	}
+
+	var hosts []string
+	if list, ok := section[hostKey].([]any); ok {
+		for _, host := range list {
+			hosts = append(hosts, fmt.Sprintf("%v", host))
+		}
+	}
+	if len(hosts) == 0 {
+		hosts = []string{base[hostKey]}
+	}
+
+	var dialectors []gorm.Dialector
+	for _, host := range hosts {
+		settings := base.merge(map[string]any{hostKey: host})
+
+		dialector, err := dialector(dbConn, driver, settings)
+		if err != nil {
+			return nil, err
+		}
+		dialectors = append(dialectors, dialector)
 	}
 
-	Connect = db
+	return dialectors, nil
 }
 
 func GetDriver(dbConn string) string {
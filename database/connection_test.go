@@ -0,0 +1,122 @@
+package database
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/galaplate/core/config"
+	"gorm.io/gorm"
+)
+
+func TestNamedConnections(t *testing.T) {
+	reportingPath := filepath.Join(t.TempDir(), "reporting.sqlite")
+	config.GetGlobal().Set("database.connections.test_reporting.driver", "sqlite")
+	config.GetGlobal().Set("database.connections.test_reporting.database", reportingPath)
+
+	reporting := Connection("test_reporting")
+	if reporting == nil {
+		t.Fatal("Expected reporting connection to open")
+	}
+	if Connection("test_reporting") != reporting {
+		t.Error("Expected the same connection to be reused")
+	}
+
+	var file string
+	reporting.Raw("SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&file)
+	if file != reportingPath {
+		t.Errorf("Expected connection to %s, got %s", reportingPath, file)
+	}
+}
+
+func TestOpenConnectionConcurrently(t *testing.T) {
+	config.GetGlobal().Set("database.connections.test_concurrent.driver", "sqlite")
+	config.GetGlobal().Set("database.connections.test_concurrent.database", filepath.Join(t.TempDir(), "concurrent.sqlite"))
+
+	var wg sync.WaitGroup
+	opened := make([]*gorm.DB, 8)
+	for i := range opened {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db, err := OpenConnection("test_concurrent")
+			if err != nil {
+				t.Errorf("Failed to open connection: %v", err)
+			}
+			opened[i] = db
+		}()
+	}
+	wg.Wait()
+
+	for _, db := range opened {
+		if db != opened[0] {
+			t.Fatal("Expected every caller to get the registered connection")
+		}
+	}
+}
+
+func TestMigrationOnNamedConnection(t *testing.T) {
+	config.GetGlobal().Set("database.connections.test_analytics.driver", "sqlite")
+	config.GetGlobal().Set("database.connections.test_analytics.database", filepath.Join(t.TempDir(), "analytics.sqlite"))
+
+	migrator, db := newChecksumMigrator(t)
+	migrator.registry.Register(&checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_posts_table", Timestamp: 1},
+		up:            createPostsTable("title"),
+	})
+	migrator.registry.Register(&checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_events_table", Timestamp: 2, Connection: "test_analytics"},
+		up: func(schema *Schema) error {
+			return schema.Create("events", func(table *Blueprint) {
+				table.ID()
+			})
+		},
+	})
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	analytics, err := NewConnectionSchema("test_analytics")
+	if err != nil {
+		t.Fatalf("Failed to open analytics schema: %v", err)
+	}
+	if !analytics.HasTable("events") || analytics.HasTable("posts") {
+		t.Error("Expected events on the analytics connection only")
+	}
+	if migrator.schema.HasTable("events") {
+		t.Error("Expected events not to be created on the default connection")
+	}
+
+	var count int64
+	db.Table("migrations").Count(&count)
+	if count != 2 {
+		t.Errorf("Expected both migrations recorded on the default connection, got %d", count)
+	}
+}
+
+func TestOpenConnectionReturnsError(t *testing.T) {
+	config.GetGlobal().Set("database.connections.test_broken.driver", "oracle")
+
+	if _, err := OpenConnection("test_broken"); err == nil {
+		t.Fatal("Expected an error for an unsupported driver")
+	}
+	if _, err := NewConnectionSchema("test_broken"); err == nil {
+		t.Fatal("Expected NewConnectionSchema to return the error")
+	}
+
+	migrator, _ := newChecksumMigrator(t)
+	migrator.registry.Register(&checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_events_table", Timestamp: 1, Connection: "test_broken"},
+		up: func(schema *Schema) error {
+			return schema.Create("events", func(table *Blueprint) {
+				table.ID()
+			})
+		},
+	})
+
+	if err := migrator.Up(); err == nil || !strings.Contains(err.Error(), "create_events_table") {
+		t.Errorf("Expected Up to return the connection error, got: %v", err)
+	}
+}
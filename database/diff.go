@@ -0,0 +1,606 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/galaplate/core/config"
+	"github.com/galaplate/core/supports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ModelRegistry holds the GORM models compared against the database by db:diff
+var ModelRegistry = []any{}
+
+// RegisterModel registers GORM models for schema diffing
+//
+//	database.RegisterModel(&models.User{}, &models.Post{})
+func RegisterModel(models ...any) {
+	ModelRegistry = append(ModelRegistry, models...)
+}
+
+// TableDiff holds the Blueprint calls needed to reconcile one table
+type TableDiff struct {
+	Table        string
+	Create       bool
+	Columns      []string // Blueprint calls adding columns
+	Indexes      []string // Blueprint calls adding indexes
+	DropColumns  []string // Blueprint calls reverting the added columns
+	DropIndexes  []string // Blueprint calls reverting the added indexes
+	Changes      []string // Blueprint calls modifying or dropping columns that differ from the model
+	Reverts      []string // Blueprint calls restoring the changed columns' database definition
+	ExtraColumns []string // Columns present in the database but not on the model
+}
+
+// SchemaDiff is the difference between the registered models and the database
+type SchemaDiff struct {
+	Tables []TableDiff
+}
+
+// IsEmpty reports whether the models and the database are in sync
+func (d *SchemaDiff) IsEmpty() bool {
+	for _, table := range d.Tables {
+		if table.Create || len(table.Columns) > 0 || len(table.Indexes) > 0 || len(table.Changes) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// UpSource returns the body of the generated migration Up method
+func (d *SchemaDiff) UpSource() string {
+	var lines []string
+
+	for _, table := range d.Tables {
+		if !table.Create && len(table.Columns) == 0 && len(table.Indexes) == 0 && len(table.Changes) == 0 {
+			continue
+		}
+
+		method := "Table"
+		if table.Create {
+			method = "Create"
+		}
+
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, fmt.Sprintf("\tif err := schema.%s(%q, func(table *database.Blueprint) {", method, table.Table))
+		calls := append(append(append([]string{}, table.Columns...), table.Changes...), table.Indexes...)
+		for _, call := range calls {
+			lines = append(lines, "\t\t"+call)
+		}
+		lines = append(lines, "\t}); err != nil {", "\t\treturn err", "\t}")
+	}
+
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, "\treturn nil")
+
+	return strings.Join(lines, "\n")
+}
+
+// DownSource returns the body of the generated migration Down method
+func (d *SchemaDiff) DownSource() string {
+	var lines []string
+
+	// Revert in reverse order so dependent tables are dropped first
+	for i := len(d.Tables) - 1; i >= 0; i-- {
+		table := d.Tables[i]
+
+		reverts := append(append(append([]string{}, table.DropIndexes...), table.DropColumns...), table.Reverts...)
+
+		if len(lines) > 0 && (table.Create || len(reverts) > 0) {
+			lines = append(lines, "")
+		}
+
+		if table.Create {
+			lines = append(lines,
+				fmt.Sprintf("\tif err := schema.DropIfExists(%q); err != nil {", table.Table),
+				"\t\treturn err",
+				"\t}",
+			)
+			continue
+		}
+
+		if len(reverts) == 0 {
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("\tif err := schema.Table(%q, func(table *database.Blueprint) {", table.Table))
+		for _, call := range reverts {
+			lines = append(lines, "\t\t"+call)
+		}
+		lines = append(lines, "\t}); err != nil {", "\t\treturn err", "\t}")
+	}
+
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, "\treturn nil")
+
+	return strings.Join(lines, "\n")
+}
+
+// SchemaDiffer compares GORM models against live database introspection
+type SchemaDiffer struct {
+	db       *gorm.DB
+	dbDriver string
+}
+
+// NewSchemaDiffer creates a new SchemaDiffer instance
+func NewSchemaDiffer() *SchemaDiffer {
+	return &SchemaDiffer{
+		db:       Connect,
+		dbDriver: supports.MapPostgres(GetDriver(config.ConfigString("database.default"))),
+	}
+}
+
+// Diff compares the given models (or all registered models) with the database
+func (d *SchemaDiffer) Diff(models ...any) (*SchemaDiff, error) {
+	if len(models) == 0 {
+		models = ModelRegistry
+	}
+
+	cache := &sync.Map{}
+	diff := &SchemaDiff{}
+
+	for _, model := range models {
+		sch, err := schema.Parse(model, cache, d.db.NamingStrategy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+
+		tableDiff, err := d.diffTable(sch)
+		if err != nil {
+			return nil, err
+		}
+		diff.Tables = append(diff.Tables, tableDiff)
+	}
+
+	return diff, nil
+}
+
+// diffTable compares a parsed model schema with its table
+func (d *SchemaDiffer) diffTable(sch *schema.Schema) (TableDiff, error) {
+	tableDiff := TableDiff{Table: sch.Table}
+	migrator := d.db.Migrator()
+
+	existing := map[string]gorm.ColumnType{}
+	if migrator.HasTable(sch.Table) {
+		columnTypes, err := migrator.ColumnTypes(sch.Table)
+		if err != nil {
+			return tableDiff, fmt.Errorf("failed to introspect table %s: %w", sch.Table, err)
+		}
+		for _, columnType := range columnTypes {
+			existing[columnType.Name()] = columnType
+		}
+	} else {
+		tableDiff.Create = true
+	}
+
+	modelColumns := map[string]bool{}
+	softDeleteIndexes := map[string]bool{}
+	var primaryKeys []string
+
+	for _, field := range sch.Fields {
+		if field.DBName == "" || field.IgnoreMigration {
+			continue
+		}
+		modelColumns[field.DBName] = true
+
+		if field.PrimaryKey && !isIDField(field) {
+			primaryKeys = append(primaryKeys, field.DBName)
+		}
+
+		if columnType, ok := existing[field.DBName]; ok {
+			if !field.PrimaryKey && columnDiffers(field, columnType) {
+				tableDiff.Changes = append(tableDiff.Changes, modifyCall(field))
+				tableDiff.Reverts = append(tableDiff.Reverts,
+					fmt.Sprintf("table.Modify(%q).%s", field.DBName, strings.TrimPrefix(existingColumnCall(columnType), "table.")))
+			}
+			continue
+		}
+
+		// SoftDeletes indexes the column itself, covering gorm.Model's index tag
+		if field.FieldType == deletedAtType {
+			softDeleteIndexes[fmt.Sprintf("idx_%s_%s", sch.Table, field.DBName)] = true
+		}
+
+		call, dropCall := fieldToBlueprint(field)
+		tableDiff.Columns = append(tableDiff.Columns, call)
+		tableDiff.DropColumns = append(tableDiff.DropColumns, dropCall)
+	}
+
+	if tableDiff.Create && len(primaryKeys) > 0 {
+		tableDiff.Indexes = append(tableDiff.Indexes, fmt.Sprintf("table.Primary(%s)", goStringSlice(primaryKeys)))
+	}
+
+	for _, idx := range sch.ParseIndexes() {
+		if softDeleteIndexes[idx.Name] {
+			continue
+		}
+		if !tableDiff.Create && migrator.HasIndex(sch.Table, idx.Name) {
+			continue
+		}
+
+		columns := make([]string, 0, len(idx.Fields))
+		for _, option := range idx.Fields {
+			columns = append(columns, option.DBName)
+		}
+
+		method := "Index"
+		if strings.EqualFold(idx.Class, "UNIQUE") {
+			method = "UniqueIndex"
+		}
+
+		call := fmt.Sprintf("table.%s(%s, %q)", method, goStringSlice(columns), idx.Name)
+		if idx.Type != "" {
+			call += fmt.Sprintf(".Using(%q)", strings.ToLower(idx.Type))
+		}
+		if idx.Where != "" {
+			call += fmt.Sprintf(".Where(%q)", idx.Where)
+		}
+
+		tableDiff.Indexes = append(tableDiff.Indexes, call)
+		tableDiff.DropIndexes = append(tableDiff.DropIndexes, fmt.Sprintf("table.DropIndex(%q)", idx.Name))
+	}
+
+	if !tableDiff.Create {
+		for column := range existing {
+			if !modelColumns[column] {
+				tableDiff.ExtraColumns = append(tableDiff.ExtraColumns, column)
+			}
+		}
+		sort.Strings(tableDiff.ExtraColumns)
+
+		// Columns dropped from the model are dropped from the table; Down re-adds
+		// them empty since their data cannot be restored
+		for _, column := range tableDiff.ExtraColumns {
+			tableDiff.Changes = append(tableDiff.Changes, fmt.Sprintf("table.DropColumn(%q) // not on the model, its data is lost", column))
+			tableDiff.Reverts = append(tableDiff.Reverts, existingColumnCall(existing[column]))
+		}
+
+		// SQLite renders Modify as a comment, so say so where the migration is read
+		if len(tableDiff.Changes) > len(tableDiff.ExtraColumns) && d.dbDriver == "sqlite" {
+			tableDiff.Changes = append([]string{fmt.Sprintf("// SQLite cannot modify columns in place; rebuild %s to apply these Modify calls", sch.Table)}, tableDiff.Changes...)
+		}
+	}
+
+	return tableDiff, nil
+}
+
+// columnTypeAliases maps driver-specific type names to the names customColumnCall knows
+var columnTypeAliases = map[string]string{
+	"int2":                        "smallint",
+	"int4":                        "integer",
+	"int8":                        "bigint",
+	"mediumint":                   "integer",
+	"serial":                      "integer",
+	"bigserial":                   "bigint",
+	"float4":                      "real",
+	"float8":                      "double precision",
+	"bpchar":                      "char",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+	"longblob":                    "blob",
+	"mediumblob":                  "blob",
+	"tinyblob":                    "blob",
+	"varbinary":                   "blob",
+	"binary":                      "blob",
+}
+
+// columnFamilies groups type names whose values convert without loss of meaning
+var columnFamilies = map[string]string{
+	"bool": "bool", "boolean": "bool",
+	"tinyint": "integer", "smallint": "integer", "int": "integer", "integer": "integer", "bigint": "integer",
+	"decimal": "numeric", "numeric": "numeric", "real": "numeric", "float": "numeric", "double": "numeric", "double precision": "numeric",
+	"varchar": "string", "character varying": "string", "char": "string", "character": "string",
+	"text": "string", "tinytext": "string", "mediumtext": "string", "longtext": "string",
+	"uuid": "string", "json": "string", "jsonb": "string", "inet": "string", "tsvector": "string",
+	"date": "time", "datetime": "time", "timestamp": "time", "timestamptz": "time", "time": "time",
+	"blob": "bytes", "bytea": "bytes",
+}
+
+// baseTypeName normalizes a type name such as "UNSIGNED INT" or "int8" to its base name
+func baseTypeName(typeName string) string {
+	name := strings.ToLower(strings.TrimSpace(typeName))
+	name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSuffix(name, " unsigned"), "unsigned "))
+	if matches := columnTypePattern.FindStringSubmatch(name); matches != nil {
+		name = strings.TrimSpace(matches[1])
+	}
+	if alias, ok := columnTypeAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// fieldFamily returns the type family of a model field, empty when unknown
+func fieldFamily(field *schema.Field) string {
+	switch field.DataType {
+	case schema.Bool:
+		return "bool"
+	case schema.Int, schema.Uint:
+		return "integer"
+	case schema.Float:
+		return "numeric"
+	case schema.String:
+		return "string"
+	case schema.Time:
+		return "time"
+	case schema.Bytes:
+		return "bytes"
+	}
+	return columnFamilies[baseTypeName(string(field.DataType))]
+}
+
+// columnDiffers reports whether an existing column's type family, size or
+// nullability differs from the model field
+func columnDiffers(field *schema.Field, columnType gorm.ColumnType) bool {
+	base := baseTypeName(columnType.DatabaseTypeName())
+
+	modelFamily, columnFamily := fieldFamily(field), columnFamilies[base]
+	// Drivers without a boolean type store it as a small integer
+	boolAsInteger := modelFamily == "bool" && columnFamily == "integer"
+	if modelFamily != "" && columnFamily != "" && modelFamily != columnFamily && !boolAsInteger {
+		return true
+	}
+
+	if field.DataType == schema.String && field.Size > 0 && (base == "varchar" || base == "character varying") {
+		if length, ok := columnType.Length(); ok && length > 0 && int(length) != field.Size {
+			return true
+		}
+	}
+
+	if field.Precision > 0 {
+		if precision, scale, ok := columnType.DecimalSize(); ok && precision > 0 &&
+			(int(precision) != field.Precision || int(scale) != field.Scale) {
+			return true
+		}
+	}
+
+	if nullable, ok := columnType.Nullable(); ok && field.FieldType != deletedAtType {
+		if nullable == field.NotNull {
+			return true
+		}
+	}
+
+	return false
+}
+
+// modifyCall returns the Blueprint call changing a column to the field's definition
+func modifyCall(field *schema.Field) string {
+	call := fmt.Sprintf("table.Modify(%q).%s", field.DBName, strings.TrimPrefix(columnCall(field), "table."))
+
+	if field.NotNull {
+		call += ".NotNullable()"
+	}
+	if field.HasDefaultValue && field.DefaultValue != "" && !field.AutoIncrement {
+		call += fmt.Sprintf(".Default(%s)", defaultLiteral(field.DefaultValue))
+	}
+
+	return call
+}
+
+// existingColumnCall returns the Blueprint call recreating a column as the
+// database reports it
+func existingColumnCall(columnType gorm.ColumnType) string {
+	typeName := baseTypeName(columnType.DatabaseTypeName())
+
+	if precision, scale, ok := columnType.DecimalSize(); ok && precision > 0 && columnFamilies[typeName] == "numeric" {
+		typeName = fmt.Sprintf("%s(%d,%d)", typeName, precision, scale)
+	} else if length, ok := columnType.Length(); ok && length > 0 && columnFamilies[typeName] == "string" && !strings.Contains(typeName, "text") {
+		typeName = fmt.Sprintf("%s(%d)", typeName, length)
+	}
+
+	call := customColumnCall(columnType.Name(), typeName)
+	if nullable, ok := columnType.Nullable(); ok && !nullable {
+		call += ".NotNullable()"
+	}
+
+	return call
+}
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+var columnTypePattern = regexp.MustCompile(`^\s*([a-zA-Z ]+?)\s*(?:\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\))?\s*(\[\])?\s*$`)
+
+// isIDField reports whether a field maps to Blueprint.ID()
+func isIDField(field *schema.Field) bool {
+	return field.PrimaryKey && field.DBName == "id" &&
+		(field.DataType == schema.Int || field.DataType == schema.Uint)
+}
+
+// fieldToBlueprint returns the Blueprint call creating the field's column and
+// the call reverting it
+func fieldToBlueprint(field *schema.Field) (string, string) {
+	name := field.DBName
+	dropCall := fmt.Sprintf("table.DropColumn(%q)", name)
+
+	if isIDField(field) {
+		return "table.ID()", dropCall
+	}
+
+	if field.FieldType == deletedAtType {
+		if name == "deleted_at" {
+			return "table.SoftDeletes()", "table.DropSoftDeletes()"
+		}
+		return fmt.Sprintf("table.SoftDeletes(%q)", name), fmt.Sprintf("table.DropSoftDeletes(%q)", name)
+	}
+
+	call := columnCall(field)
+
+	if field.NotNull || field.PrimaryKey {
+		call += ".NotNullable()"
+	}
+	if field.Unique {
+		call += ".Unique()"
+	}
+	if field.HasDefaultValue && field.DefaultValue != "" && !field.AutoIncrement {
+		call += fmt.Sprintf(".Default(%s)", defaultLiteral(field.DefaultValue))
+	}
+	if field.Comment != "" {
+		call += fmt.Sprintf(".Comment(%q)", field.Comment)
+	}
+
+	return call, dropCall
+}
+
+// columnCall maps a GORM field type to a Blueprint column method
+func columnCall(field *schema.Field) string {
+	name := field.DBName
+
+	switch field.DataType {
+	case schema.Bool:
+		return fmt.Sprintf("table.Boolean(%q)", name)
+	case schema.Int, schema.Uint:
+		var call string
+		switch field.Size {
+		case 8:
+			call = fmt.Sprintf("table.TinyInt(%q)", name)
+		case 16:
+			call = fmt.Sprintf("table.SmallInt(%q)", name)
+		case 32:
+			call = fmt.Sprintf("table.Integer(%q)", name)
+		default:
+			call = fmt.Sprintf("table.BigInteger(%q)", name)
+		}
+		if field.DataType == schema.Uint {
+			call += ".Unsigned()"
+		}
+		return call
+	case schema.Float:
+		if field.Precision > 0 {
+			return fmt.Sprintf("table.Decimal(%q, %d, %d)", name, field.Precision, field.Scale)
+		}
+		if field.Size == 32 {
+			return fmt.Sprintf("table.Float(%q)", name)
+		}
+		return fmt.Sprintf("table.Double(%q)", name)
+	case schema.String:
+		if field.Size > 0 && field.Size <= 65535 {
+			return fmt.Sprintf("table.String(%q, %d)", name, field.Size)
+		}
+		return fmt.Sprintf("table.Text(%q)", name)
+	case schema.Time:
+		return fmt.Sprintf("table.Timestamp(%q)", name)
+	case schema.Bytes:
+		return fmt.Sprintf("table.Blob(%q)", name)
+	}
+
+	return customColumnCall(name, string(field.DataType))
+}
+
+// customColumnCall maps an explicit gorm "type:" tag to a Blueprint column method
+func customColumnCall(name, dataType string) string {
+	matches := columnTypePattern.FindStringSubmatch(dataType)
+	if matches == nil {
+		return fmt.Sprintf("table.AddColumn(%q, %q)", dataType, name)
+	}
+
+	base := strings.ToLower(strings.TrimSpace(matches[1]))
+	length, _ := strconv.Atoi(matches[2])
+	scale, _ := strconv.Atoi(matches[3])
+
+	if matches[4] != "" {
+		return fmt.Sprintf("table.Array(%q, %q)", name, base)
+	}
+
+	withLength := func(method string) string {
+		if length > 0 {
+			return fmt.Sprintf("table.%s(%q, %d)", method, name, length)
+		}
+		return fmt.Sprintf("table.%s(%q)", method, name)
+	}
+
+	switch base {
+	case "varchar", "character varying":
+		return withLength("String")
+	case "char", "character":
+		return withLength("Char")
+	case "text":
+		return fmt.Sprintf("table.Text(%q)", name)
+	case "tinytext":
+		return fmt.Sprintf("table.TinyText(%q)", name)
+	case "mediumtext":
+		return fmt.Sprintf("table.MediumText(%q)", name)
+	case "longtext":
+		return fmt.Sprintf("table.LongText(%q)", name)
+	case "json":
+		return fmt.Sprintf("table.JSON(%q)", name)
+	case "jsonb":
+		return fmt.Sprintf("table.JSONB(%q)", name)
+	case "uuid":
+		return fmt.Sprintf("table.UUID(%q)", name)
+	case "date":
+		return fmt.Sprintf("table.Date(%q)", name)
+	case "datetime":
+		return fmt.Sprintf("table.DateTime(%q)", name)
+	case "timestamp", "timestamptz":
+		return fmt.Sprintf("table.Timestamp(%q)", name)
+	case "time":
+		return fmt.Sprintf("table.Time(%q)", name)
+	case "decimal", "numeric":
+		if length == 0 {
+			length, scale = 10, 2
+		}
+		return fmt.Sprintf("table.Decimal(%q, %d, %d)", name, length, scale)
+	case "bool", "boolean":
+		return fmt.Sprintf("table.Boolean(%q)", name)
+	case "tinyint":
+		return fmt.Sprintf("table.TinyInt(%q)", name)
+	case "smallint":
+		return fmt.Sprintf("table.SmallInt(%q)", name)
+	case "int", "integer":
+		return fmt.Sprintf("table.Integer(%q)", name)
+	case "bigint":
+		return fmt.Sprintf("table.BigInteger(%q)", name)
+	case "float", "real":
+		return fmt.Sprintf("table.Float(%q)", name)
+	case "double", "double precision":
+		return fmt.Sprintf("table.Double(%q)", name)
+	case "blob", "bytea":
+		return fmt.Sprintf("table.Blob(%q)", name)
+	case "inet":
+		return fmt.Sprintf("table.Inet(%q)", name)
+	case "tsvector":
+		return fmt.Sprintf("table.TsVector(%q)", name)
+	}
+
+	return fmt.Sprintf("table.AddColumn(%q, %q)", dataType, name)
+}
+
+// defaultLiteral converts a gorm default tag value to a Go literal for Blueprint.Default
+func defaultLiteral(value string) string {
+	trimmed := strings.TrimSpace(value)
+	upper := strings.ToUpper(trimmed)
+
+	switch {
+	case upper == "CURRENT_TIMESTAMP" || upper == "NOW()":
+		return `"CURRENT_TIMESTAMP"`
+	case upper == "TRUE" || upper == "FALSE":
+		return strings.ToLower(trimmed)
+	}
+
+	if _, err := strconv.ParseFloat(trimmed, 64); err == nil {
+		return trimmed
+	}
+
+	return strconv.Quote(strings.Trim(trimmed, `'"`))
+}
+
+// goStringSlice renders a []string literal
+func goStringSlice(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return fmt.Sprintf("[]string{%s}", strings.Join(quoted, ", "))
+}
@@ -0,0 +1,343 @@
+package database
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galaplate/core/config"
+	"github.com/galaplate/core/supports"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// connectionSettings holds the scalar settings of a connection, such as host,
+// port, sslmode or timezone, keyed as in database.yaml
+type connectionSettings map[string]string
+
+// merge returns a copy of the settings overridden by the scalar values of section
+func (s connectionSettings) merge(section map[string]any) connectionSettings {
+	merged := connectionSettings{}
+	for key, value := range s {
+		merged[key] = value
+	}
+	for key, value := range section {
+		switch value.(type) {
+		case nil, []any, map[string]any:
+			continue
+		}
+		merged[key] = fmt.Sprintf("%v", value)
+	}
+	return merged
+}
+
+// first returns the first non-empty setting among keys
+func (s connectionSettings) first(keys ...string) string {
+	for _, key := range keys {
+		if value := s[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// duration parses a setting given as a Go duration ("30m") or a number of seconds
+func (s connectionSettings) duration(key string) time.Duration {
+	return parseDuration(s[key])
+}
+
+// parseDuration parses a Go duration or a number of seconds, 0 when invalid
+func parseDuration(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	d, _ := time.ParseDuration(value)
+	return d
+}
+
+// dialector builds the GORM dialector for a driver. A dsn (or url) setting is
+// used as given, otherwise the DSN is assembled from the structured settings
+func dialector(dbConn, driver string, settings connectionSettings) (gorm.Dialector, error) {
+	switch driver {
+	case "postgres":
+		return postgres.Open(postgresDSN(settings)), nil
+	case "mysql":
+		dsn, err := mysqlDSN(dbConn, settings)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mysql settings for connection %q: %w", dbConn, err)
+		}
+		return mysql.Open(dsn), nil
+	case "sqlite":
+		dsn := settings.first("dsn", "url", "database")
+		if dsn == "" {
+			dsn = "db/database.sqlite"
+		}
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database type %q for connection %q", driver, dbConn)
+	}
+}
+
+// postgresOptions maps database.yaml settings to libpq connection parameters
+var postgresOptions = map[string]string{
+	"sslmode":     "sslmode",
+	"sslrootcert": "sslrootcert",
+	"sslcert":     "sslcert",
+	"sslkey":      "sslkey",
+	"timezone":    "TimeZone",
+	"search_path": "search_path",
+}
+
+// postgresDSN builds a libpq keyword DSN. sslmode defaults to disable and socket
+// is the directory of the server unix socket
+func postgresDSN(settings connectionSettings) string {
+	options := map[string]string{}
+	for key, param := range postgresOptions {
+		if value := settings[key]; value != "" {
+			options[param] = value
+		}
+	}
+
+	if dsn := settings.first("dsn", "url"); dsn != "" {
+		if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+			return dsn
+		}
+
+		// Structured settings fill in what the URL leaves out
+		parsed, err := url.Parse(dsn)
+		if err != nil {
+			return dsn
+		}
+		query := parsed.Query()
+		for param, value := range options {
+			if query.Get(param) == "" {
+				query.Set(param, value)
+			}
+		}
+		parsed.RawQuery = query.Encode()
+		return parsed.String()
+	}
+
+	if _, ok := options["sslmode"]; !ok {
+		options["sslmode"] = "disable"
+	}
+	options["host"] = settings.first("socket", "host")
+	options["port"] = settings["port"]
+	options["user"] = settings["username"]
+	options["password"] = settings["password"]
+	options["dbname"] = settings["database"]
+
+	params := make([]string, 0, len(options))
+	for param, value := range options {
+		if value != "" || param == "password" {
+			params = append(params, param+"="+quotePostgresValue(value))
+		}
+	}
+	sort.Strings(params)
+
+	return strings.Join(params, " ")
+}
+
+// quotePostgresValue quotes a keyword DSN value when it is empty or holds spaces or quotes
+func quotePostgresValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+// mysqlDSN builds a go-sql-driver DSN. charset defaults to utf8mb4 and timezone to
+// the local one. tls accepts true, false, skip-verify and preferred, and
+// sslrootcert/sslcert/sslkey register a custom TLS configuration for the connection
+func mysqlDSN(dbConn string, settings connectionSettings) (string, error) {
+	dsn := settings.first("dsn", "url")
+	if dsn != "" && !strings.HasPrefix(dsn, "mysql://") {
+		return dsn, nil
+	}
+
+	cfg := mysqldriver.NewConfig()
+	cfg.ParseTime = true
+	cfg.Loc = time.Local
+	cfg.Params = map[string]string{"charset": "utf8mb4"}
+
+	if dsn != "" {
+		parsed, err := url.Parse(dsn)
+		if err != nil {
+			return "", err
+		}
+		cfg.User = parsed.User.Username()
+		cfg.Passwd, _ = parsed.User.Password()
+		cfg.Net = "tcp"
+		cfg.Addr = parsed.Host
+		cfg.DBName = strings.TrimPrefix(parsed.Path, "/")
+		for param, values := range parsed.Query() {
+			cfg.Params[param] = values[0]
+		}
+	} else {
+		cfg.User = settings["username"]
+		cfg.Passwd = settings["password"]
+		cfg.DBName = settings["database"]
+		if socket := settings["socket"]; socket != "" {
+			cfg.Net = "unix"
+			cfg.Addr = socket
+		} else {
+			cfg.Net = "tcp"
+			cfg.Addr = settings["host"]
+			if port := settings["port"]; port != "" {
+				cfg.Addr = net.JoinHostPort(settings["host"], port)
+			}
+		}
+	}
+
+	if charset := settings["charset"]; charset != "" {
+		cfg.Params["charset"] = charset
+	}
+	if collation := settings["collation"]; collation != "" {
+		cfg.Collation = collation
+	}
+	if timezone := settings["timezone"]; timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return "", err
+		}
+		cfg.Loc = loc
+	}
+
+	cfg.TLSConfig = settings["tls"]
+	if settings.first("sslrootcert", "sslcert") != "" && cfg.TLSConfig != "false" {
+		tlsConfig, err := mysqlTLSConfig(settings)
+		if err != nil {
+			return "", err
+		}
+		cfg.TLSConfig = "galaplate_" + dbConn
+		if err := mysqldriver.RegisterTLSConfig(cfg.TLSConfig, tlsConfig); err != nil {
+			return "", err
+		}
+	}
+
+	return cfg.FormatDSN(), nil
+}
+
+// mysqlTLSConfig loads the CA and client certificates of a MySQL connection
+func mysqlTLSConfig(settings connectionSettings) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: settings["tls"] == "skip-verify",
+	}
+
+	if rootCert := settings["sslrootcert"]; rootCert != "" {
+		pem, err := os.ReadFile(rootCert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", rootCert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cert := settings["sslcert"]; cert != "" {
+		pair, err := tls.LoadX509KeyPair(cert, settings["sslkey"])
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	return tlsConfig, nil
+}
+
+// ConnectionParams is the address and credentials of a connection, for external
+// tools such as pg_dump and mysqldump. Socket is set instead of Host and Port for
+// MySQL unix sockets, PostgreSQL takes the socket directory as Host
+type ConnectionParams struct {
+	Driver   string
+	Host     string
+	Port     string
+	Socket   string
+	Database string
+	Username string
+	Password string
+}
+
+// ResolveConnectionParams resolves a connection the way Open does, from its dsn or
+// url when set and from the structured settings otherwise. With a write section
+// the first write host is used
+func ResolveConnectionParams(dbConn string) (ConnectionParams, error) {
+	driver := supports.MapPostgres(GetDriver(dbConn))
+
+	settings := baseConnectionSettings(dbConn)
+	if section, ok := config.Config(fmt.Sprintf("database.connections.%s.write", dbConn)).(map[string]any); ok {
+		settings = settings.merge(section)
+		hostKey := "host"
+		if driver == "sqlite" {
+			hostKey = "database"
+		}
+		if list, ok := section[hostKey].([]any); ok && len(list) > 0 {
+			settings = settings.merge(map[string]any{hostKey: list[0]})
+		}
+	}
+
+	return connectionParams(dbConn, driver, settings)
+}
+
+// connectionParams parses the DSN the driver would connect with
+func connectionParams(dbConn, driver string, settings connectionSettings) (ConnectionParams, error) {
+	params := ConnectionParams{Driver: driver}
+
+	switch driver {
+	case "postgres":
+		cfg, err := pgconn.ParseConfig(postgresDSN(settings))
+		if err != nil {
+			return params, fmt.Errorf("invalid postgres settings for connection %q: %w", dbConn, err)
+		}
+		params.Host = cfg.Host
+		params.Port = strconv.Itoa(int(cfg.Port))
+		params.Database = cfg.Database
+		params.Username = cfg.User
+		params.Password = cfg.Password
+	case "mysql":
+		dsn, err := mysqlDSN(dbConn, settings)
+		if err != nil {
+			return params, fmt.Errorf("invalid mysql settings for connection %q: %w", dbConn, err)
+		}
+		cfg, err := mysqldriver.ParseDSN(dsn)
+		if err != nil {
+			return params, fmt.Errorf("invalid mysql settings for connection %q: %w", dbConn, err)
+		}
+		if cfg.Net == "unix" {
+			params.Socket = cfg.Addr
+		} else if host, port, err := net.SplitHostPort(cfg.Addr); err == nil {
+			params.Host, params.Port = host, port
+		} else {
+			params.Host, params.Port = cfg.Addr, "3306"
+		}
+		params.Database = cfg.DBName
+		params.Username = cfg.User
+		params.Password = cfg.Passwd
+	case "sqlite":
+		params.Database = settings.first("dsn", "url", "database")
+		if params.Database == "" {
+			params.Database = "db/database.sqlite"
+		}
+	default:
+		return params, fmt.Errorf("unsupported database type %q for connection %q", driver, dbConn)
+	}
+
+	return params, nil
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostgresDSN(t *testing.T) {
+	dsn := postgresDSN(connectionSettings{
+		"host":        "db",
+		"port":        "5432",
+		"username":    "app",
+		"password":    "it's secret",
+		"database":    "app",
+		"sslmode":     "verify-full",
+		"sslrootcert": "/certs/ca.pem",
+		"timezone":    "UTC",
+		"search_path": "tenant,public",
+	})
+
+	expected := `TimeZone=UTC dbname=app host=db password='it\'s secret' port=5432 search_path=tenant,public sslmode=verify-full sslrootcert=/certs/ca.pem user=app`
+	if dsn != expected {
+		t.Errorf("Expected %s, got %s", expected, dsn)
+	}
+
+	if dsn := postgresDSN(connectionSettings{"socket": "/var/run/postgresql", "database": "app"}); !strings.Contains(dsn, "host=/var/run/postgresql") || !strings.Contains(dsn, "sslmode=disable") {
+		t.Errorf("Expected the socket directory as host and sslmode=disable by default, got %s", dsn)
+	}
+
+	dsn = postgresDSN(connectionSettings{"url": "postgres://app:secret@db:5432/app?sslmode=require", "sslmode": "disable", "timezone": "UTC"})
+	if !strings.Contains(dsn, "sslmode=require") || !strings.Contains(dsn, "TimeZone=UTC") {
+		t.Errorf("Expected the URL to keep its sslmode and gain the timezone, got %s", dsn)
+	}
+}
+
+func TestMySQLDSN(t *testing.T) {
+	dsn, err := mysqlDSN("test", connectionSettings{
+		"host":      "db",
+		"port":      "3306",
+		"username":  "app",
+		"password":  "secret",
+		"database":  "app",
+		"collation": "utf8mb4_unicode_ci",
+		"timezone":  "UTC",
+		"tls":       "skip-verify",
+	})
+	if err != nil {
+		t.Fatalf("Failed to build DSN: %v", err)
+	}
+
+	expected := "app:secret@tcp(db:3306)/app?collation=utf8mb4_unicode_ci&parseTime=true&tls=skip-verify&charset=utf8mb4"
+	if dsn != expected {
+		t.Errorf("Expected %s, got %s", expected, dsn)
+	}
+
+	dsn, _ = mysqlDSN("test", connectionSettings{"socket": "/var/run/mysqld/mysqld.sock", "username": "app", "database": "app", "timezone": "UTC"})
+	if !strings.HasPrefix(dsn, "app@unix(/var/run/mysqld/mysqld.sock)/app") {
+		t.Errorf("Expected a unix socket DSN, got %s", dsn)
+	}
+
+	dsn, _ = mysqlDSN("test", connectionSettings{"url": "mysql://app:p%40ss@db:3307/app?timeout=5s", "timezone": "UTC"})
+	if !strings.HasPrefix(dsn, "app:p@ss@tcp(db:3307)/app?") || !strings.Contains(dsn, "timeout=5s") {
+		t.Errorf("Expected the URL to be converted, got %s", dsn)
+	}
+
+	raw := "app:secret@tcp(db:3306)/app"
+	if dsn, _ := mysqlDSN("test", connectionSettings{"dsn": raw}); dsn != raw {
+		t.Errorf("Expected a raw DSN to be used as given, got %s", dsn)
+	}
+}
+
+func TestConnectionParams(t *testing.T) {
+	params, err := connectionParams("test", "postgres", connectionSettings{"url": "postgres://app:secret@db:6432/app?sslmode=disable", "timezone": "UTC"})
+	if err != nil {
+		t.Fatalf("Failed to resolve postgres params: %v", err)
+	}
+	if params.Host != "db" || params.Port != "6432" || params.Database != "app" || params.Username != "app" || params.Password != "secret" {
+		t.Errorf("Unexpected postgres params from url: %+v", params)
+	}
+
+	params, err = connectionParams("test", "mysql", connectionSettings{"dsn": "app:secret@tcp(db:3307)/app?parseTime=true"})
+	if err != nil {
+		t.Fatalf("Failed to resolve mysql params: %v", err)
+	}
+	if params.Host != "db" || params.Port != "3307" || params.Database != "app" || params.Username != "app" || params.Password != "secret" {
+		t.Errorf("Unexpected mysql params from dsn: %+v", params)
+	}
+
+	params, _ = connectionParams("test", "mysql", connectionSettings{"socket": "/var/run/mysqld/mysqld.sock", "username": "app", "database": "app"})
+	if params.Socket != "/var/run/mysqld/mysqld.sock" || params.Host != "" {
+		t.Errorf("Expected a socket instead of a host, got: %+v", params)
+	}
+}
+
+func TestConnectionSettingsDuration(t *testing.T) {
+	settings := connectionSettings{"conn_max_lifetime": "30m", "conn_max_idle_time": "90"}
+
+	if d := settings.duration("conn_max_lifetime"); d != 30*time.Minute {
+		t.Errorf("Expected 30m, got %s", d)
+	}
+	if d := settings.duration("conn_max_idle_time"); d != 90*time.Second {
+		t.Errorf("Expected 90s, got %s", d)
+	}
+}
@@ -0,0 +1,511 @@
+package database
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galaplate/core/config"
+	"github.com/galaplate/core/supports"
+	"gorm.io/gorm"
+)
+
+// DefaultDumpBatchSize is the number of rows per INSERT statement
+const DefaultDumpBatchSize = 500
+
+// DumpOptions configures a native database dump
+type DumpOptions struct {
+	// Include limits the dump to matching tables, shell patterns such as "audit_*" are allowed
+	Include []string
+
+	// Exclude skips matching tables
+	Exclude []string
+
+	// ExcludeData dumps the structure of matching tables without their rows
+	ExcludeData []string
+
+	// BatchSize is the number of rows per INSERT, DefaultDumpBatchSize when zero
+	BatchSize int
+}
+
+// Dumper dumps and restores a database as SQL without external binaries
+type Dumper struct {
+	db       *gorm.DB
+	dbDriver string
+}
+
+// NewDumper creates a new Dumper on the default connection
+func NewDumper() *Dumper {
+	return &Dumper{
+		db:       Connect,
+		dbDriver: supports.MapPostgres(GetDriver(config.ConfigString("database.default"))),
+	}
+}
+
+// Dump streams the schema and data of the selected tables to w. Every table is
+// read in one read-only transaction, so the dump is a consistent snapshot while
+// the application keeps writing
+func (d *Dumper) Dump(w io.Writer, opts DumpOptions) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultDumpBatchSize
+	}
+
+	// SQLite has no isolation levels, a read transaction already sees a snapshot
+	txOptions := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	if d.dbDriver == "sqlite" {
+		txOptions = &sql.TxOptions{}
+	}
+
+	tx := d.db.Begin(txOptions)
+	if tx.Error != nil {
+		return fmt.Errorf("failed to start snapshot transaction: %w", tx.Error)
+	}
+	defer tx.Rollback()
+
+	return (&Dumper{db: tx, dbDriver: d.dbDriver}).dump(w, opts)
+}
+
+// dump writes the dump on the snapshot transaction
+func (d *Dumper) dump(w io.Writer, opts DumpOptions) error {
+	tables, err := d.tables()
+	if err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "-- Galaplate %s dump generated at %s\n\n", d.dbDriver, time.Now().Format(time.RFC3339))
+	switch d.dbDriver {
+	case "mysql":
+		fmt.Fprintln(out, "SET FOREIGN_KEY_CHECKS=0;")
+	case "sqlite":
+		fmt.Fprintln(out, "PRAGMA foreign_keys=OFF;")
+	}
+
+	var deferred []string
+	for _, table := range tables {
+		if !matchesTable(table, opts.Include, true) || matchesTable(table, opts.Exclude, false) {
+			continue
+		}
+
+		fmt.Fprintf(out, "\n-- Table %s\n", table)
+
+		create, after, err := d.tableSchema(table)
+		if err != nil {
+			return fmt.Errorf("failed to read schema of %s: %w", table, err)
+		}
+		fmt.Fprintf(out, "%s;\n%s;\n", d.dropTableSQL(table), create)
+
+		if !matchesTable(table, opts.ExcludeData, false) {
+			if err := d.dumpRows(out, table, opts.BatchSize); err != nil {
+				return fmt.Errorf("failed to dump rows of %s: %w", table, err)
+			}
+		}
+
+		deferred = append(deferred, after...)
+	}
+
+	// Indexes, foreign keys and sequence positions come after the data
+	if len(deferred) > 0 {
+		fmt.Fprintln(out)
+		for _, statement := range deferred {
+			fmt.Fprintf(out, "%s;\n", statement)
+		}
+	}
+
+	switch d.dbDriver {
+	case "mysql":
+		fmt.Fprintln(out, "\nSET FOREIGN_KEY_CHECKS=1;")
+	case "sqlite":
+		fmt.Fprintln(out, "\nPRAGMA foreign_keys=ON;")
+	}
+
+	return out.Flush()
+}
+
+// Restore executes a dump, gzip compressed or not, statement by statement
+func (d *Dumper) Restore(r io.Reader) error {
+	reader := bufio.NewReader(r)
+
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = bufio.NewReader(gz)
+	}
+
+	sqlDB, err := d.db.DB()
+	if err != nil {
+		return err
+	}
+
+	// Session settings such as FOREIGN_KEY_CHECKS must apply to every statement
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	scanner := newStatementScanner(reader, d.dbDriver == "mysql")
+	for {
+		statement, err := scanner.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to restore statement %q: %w", truncateStatement(statement), err)
+		}
+	}
+}
+
+// tables lists the base tables of the database
+func (d *Dumper) tables() ([]string, error) {
+	var query string
+	switch d.dbDriver {
+	case "mysql":
+		query = "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
+	case "postgres":
+		query = "SELECT tablename FROM pg_tables WHERE schemaname = current_schema() ORDER BY tablename"
+	case "sqlite":
+		query = "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name"
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", d.dbDriver)
+	}
+
+	var tables []string
+	err := d.db.Raw(query).Scan(&tables).Error
+	return tables, err
+}
+
+// tableSchema returns the CREATE TABLE statement of a table and the
+// statements to run once its data is loaded
+func (d *Dumper) tableSchema(table string) (string, []string, error) {
+	switch d.dbDriver {
+	case "mysql":
+		var name, create string
+		if err := d.db.Raw("SHOW CREATE TABLE "+d.quote(table)).Row().Scan(&name, &create); err != nil {
+			return "", nil, err
+		}
+		return create, nil, nil
+	case "sqlite":
+		var create string
+		if err := d.db.Raw("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", table).Row().Scan(&create); err != nil {
+			return "", nil, err
+		}
+		var after []string
+		err := d.db.Raw("SELECT sql FROM sqlite_master WHERE type IN ('index', 'trigger') AND tbl_name = ? AND sql IS NOT NULL ORDER BY type, name", table).Scan(&after).Error
+		return create, after, err
+	default:
+		return d.postgresTableSchema(table)
+	}
+}
+
+// postgresTableSchema rebuilds a CREATE TABLE statement from the PostgreSQL catalog
+func (d *Dumper) postgresTableSchema(table string) (string, []string, error) {
+	relation := d.quote(table)
+
+	var columns []struct {
+		Name      string
+		Type      string
+		NotNull   bool
+		Default   sql.NullString
+		Identity  string
+		Generated string
+	}
+	err := d.db.Raw(`SELECT a.attname AS name, format_type(a.atttypid, a.atttypmod) AS type,
+			a.attnotnull AS not_null, pg_get_expr(ad.adbin, ad.adrelid) AS "default",
+			a.attidentity::text AS identity, a.attgenerated::text AS generated
+		FROM pg_attribute a
+		LEFT JOIN pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum
+		WHERE a.attrelid = ?::regclass AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`, relation).Scan(&columns).Error
+	if err != nil {
+		return "", nil, err
+	}
+
+	var definitions []string
+	var after []string
+
+	for _, col := range columns {
+		definition := d.quote(col.Name) + " " + col.Type
+
+		switch {
+		case col.Generated == "s":
+			definition += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", col.Default.String)
+		case col.Identity == "a":
+			definition += " GENERATED ALWAYS AS IDENTITY"
+		case col.Identity == "d":
+			definition += " GENERATED BY DEFAULT AS IDENTITY"
+		case strings.HasPrefix(col.Default.String, "nextval("):
+			definition = d.quote(col.Name) + " " + serialType(col.Type)
+		case col.Default.Valid:
+			definition += " DEFAULT " + col.Default.String
+		}
+
+		if col.NotNull && col.Identity == "" {
+			definition += " NOT NULL"
+		}
+		definitions = append(definitions, definition)
+
+		if col.Identity != "" || strings.HasPrefix(col.Default.String, "nextval(") {
+			after = append(after, fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)",
+				strings.ReplaceAll(relation, "'", "''"), strings.ReplaceAll(col.Name, "'", "''"), d.quote(col.Name), relation))
+		}
+	}
+
+	var constraints []struct {
+		Name       string
+		Type       string
+		Definition string
+	}
+	err = d.db.Raw(`SELECT conname AS name, contype::text AS type, pg_get_constraintdef(oid) AS definition
+		FROM pg_constraint WHERE conrelid = ?::regclass ORDER BY contype, conname`, relation).Scan(&constraints).Error
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, constraint := range constraints {
+		sql := fmt.Sprintf("CONSTRAINT %s %s", d.quote(constraint.Name), constraint.Definition)
+		if constraint.Type == "f" {
+			// Foreign keys are added once every table is loaded
+			after = append(after, fmt.Sprintf("ALTER TABLE %s ADD %s", relation, sql))
+			continue
+		}
+		definitions = append(definitions, sql)
+	}
+
+	var indexes []string
+	err = d.db.Raw(`SELECT indexdef FROM pg_indexes
+		WHERE schemaname = current_schema() AND tablename = ?
+		AND indexname NOT IN (SELECT conname FROM pg_constraint WHERE conrelid = ?::regclass)
+		ORDER BY indexname`, table, relation).Scan(&indexes).Error
+	if err != nil {
+		return "", nil, err
+	}
+	after = append(after, indexes...)
+
+	create := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", relation, strings.Join(definitions, ",\n  "))
+	return create, after, nil
+}
+
+// dumpRows writes the rows of a table as batched INSERT statements
+func (d *Dumper) dumpRows(out io.Writer, table string, batchSize int) error {
+	columns, err := d.insertableColumns(table)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = d.quote(column)
+	}
+
+	rows, err := d.db.Raw(fmt.Sprintf("SELECT %s FROM %s", strings.Join(quoted, ", "), d.quote(table))).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s)", d.quote(table), strings.Join(quoted, ", "))
+	if d.dbDriver == "postgres" {
+		insert += " OVERRIDING SYSTEM VALUE"
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var batch []string
+	flush := func() {
+		if len(batch) > 0 {
+			fmt.Fprintf(out, "%s VALUES\n  %s;\n", insert, strings.Join(batch, ",\n  "))
+			batch = batch[:0]
+		}
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+
+		literals := make([]string, len(values))
+		for i, value := range values {
+			literals[i] = d.literal(value, columnTypes[i].DatabaseTypeName())
+		}
+		batch = append(batch, "("+strings.Join(literals, ", ")+")")
+
+		if len(batch) >= batchSize {
+			flush()
+		}
+	}
+	flush()
+
+	return rows.Err()
+}
+
+// insertableColumns lists the columns of a table, leaving out generated columns
+func (d *Dumper) insertableColumns(table string) ([]string, error) {
+	var columns []string
+	var err error
+
+	switch d.dbDriver {
+	case "mysql":
+		err = d.db.Raw(`SELECT COLUMN_NAME FROM information_schema.COLUMNS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND EXTRA NOT LIKE '%GENERATED%'
+			ORDER BY ORDINAL_POSITION`, table).Scan(&columns).Error
+	case "postgres":
+		err = d.db.Raw(`SELECT attname FROM pg_attribute
+			WHERE attrelid = ?::regclass AND attnum > 0 AND NOT attisdropped AND attgenerated = ''
+			ORDER BY attnum`, d.quote(table)).Scan(&columns).Error
+	default:
+		err = d.db.Raw("SELECT name FROM pragma_table_xinfo(?) WHERE hidden = 0 ORDER BY cid", table).Scan(&columns).Error
+	}
+
+	return columns, err
+}
+
+// literal renders a scanned value as a SQL literal for the driver
+func (d *Dumper) literal(value any, databaseType string) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if d.dbDriver == "sqlite" {
+			if v {
+				return "1"
+			}
+			return "0"
+		}
+		return strings.ToUpper(strconv.FormatBool(v))
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		switch d.dbDriver {
+		case "mysql":
+			return d.quoteString(v.Format("2006-01-02 15:04:05.999999"))
+		case "sqlite":
+			return d.quoteString(v.Format("2006-01-02 15:04:05.999999999-07:00"))
+		default:
+			return d.quoteString(v.Format("2006-01-02 15:04:05.999999-07:00"))
+		}
+	case []byte:
+		if isBinaryType(d.dbDriver, databaseType) {
+			if d.dbDriver == "postgres" {
+				return `'\x` + hex.EncodeToString(v) + `'`
+			}
+			return "X'" + hex.EncodeToString(v) + "'"
+		}
+		return d.quoteString(string(v))
+	case string:
+		return d.quoteString(v)
+	case driver.Valuer:
+		resolved, err := v.Value()
+		if err != nil {
+			return "NULL"
+		}
+		return d.literal(resolved, databaseType)
+	default:
+		return d.quoteString(fmt.Sprint(v))
+	}
+}
+
+// quoteString quotes a string literal, escaping backslashes on MySQL
+func (d *Dumper) quoteString(value string) string {
+	if d.dbDriver == "mysql" {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// quote quotes an identifier for the driver
+func (d *Dumper) quote(identifier string) string {
+	if d.dbDriver == "mysql" {
+		return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// dropTableSQL drops a table before it is recreated
+func (d *Dumper) dropTableSQL(table string) string {
+	if d.dbDriver == "postgres" {
+		return fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", d.quote(table))
+	}
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s", d.quote(table))
+}
+
+// isBinaryType reports whether a column holds raw bytes rather than text
+func isBinaryType(driver, databaseType string) bool {
+	databaseType = strings.ToUpper(databaseType)
+
+	switch driver {
+	case "mysql":
+		return strings.Contains(databaseType, "BLOB") || strings.Contains(databaseType, "BINARY") ||
+			databaseType == "BIT" || databaseType == "GEOMETRY"
+	case "postgres":
+		return databaseType == "BYTEA"
+	default:
+		// mattn/go-sqlite3 returns TEXT as string, so []byte is always a BLOB
+		return true
+	}
+}
+
+// serialType maps an integer type to its PostgreSQL serial equivalent
+func serialType(columnType string) string {
+	switch columnType {
+	case "bigint":
+		return "bigserial"
+	case "smallint":
+		return "smallserial"
+	default:
+		return "serial"
+	}
+}
+
+// matchesTable reports whether a table matches any of the patterns. An empty
+// pattern list matches when emptyMatches is set
+func matchesTable(table string, patterns []string, emptyMatches bool) bool {
+	if len(patterns) == 0 {
+		return emptyMatches
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, table); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateStatement shortens a statement for error messages
+func truncateStatement(statement string) string {
+	if len(statement) > 120 {
+		return statement[:120] + "..."
+	}
+	return statement
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newDumpDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "dump.sqlite")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+	return db
+}
+
+func TestNativeDumpAndRestore(t *testing.T) {
+	source := newDumpDatabase(t)
+	schema := &Schema{db: source, dbDriver: "sqlite"}
+
+	if err := schema.Create("notes", func(table *Blueprint) {
+		table.ID()
+		table.String("title").NotNullable()
+		table.Text("body")
+		table.Blob("attachment")
+		table.Boolean("pinned").Default(false)
+		table.Timestamp("created_at")
+		table.Index([]string{"title"})
+	}); err != nil {
+		t.Fatalf("Failed to create notes: %v", err)
+	}
+	if err := schema.Create("audit_logs", func(table *Blueprint) {
+		table.ID()
+		table.String("event")
+	}); err != nil {
+		t.Fatalf("Failed to create audit_logs: %v", err)
+	}
+
+	createdAt := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		source.Exec("INSERT INTO notes (title, body, attachment, pinned, created_at) VALUES (?, ?, ?, ?, ?)",
+			"it's; note", `line\nwith "quotes"`, []byte{0x00, 0xff}, i%2 == 0, createdAt)
+	}
+	source.Exec("INSERT INTO audit_logs (event) VALUES ('login')")
+
+	var buf bytes.Buffer
+	dumper := &Dumper{db: source, dbDriver: "sqlite"}
+	if err := dumper.Dump(&buf, DumpOptions{Exclude: []string{"audit_*"}, BatchSize: 2}); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	dump := buf.String()
+	if strings.Contains(dump, "audit_logs") {
+		t.Error("Excluded table should not be dumped")
+	}
+	if count := strings.Count(dump, `INSERT INTO "notes"`); count != 3 {
+		t.Errorf("Expected 5 rows in batches of 2 (3 INSERTs), got %d", count)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(dump), "PRAGMA foreign_keys=ON;") {
+		t.Error("Expected the dump to re-enable foreign keys at the end")
+	}
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(buf.Bytes())
+	gz.Close()
+
+	target := newDumpDatabase(t)
+	if err := (&Dumper{db: target, dbDriver: "sqlite"}).Restore(&compressed); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	var notes []struct {
+		Title      string
+		Body       string
+		Attachment []byte
+		Pinned     bool
+		CreatedAt  time.Time
+	}
+	target.Table("notes").Order("id").Find(&notes)
+
+	if len(notes) != 5 {
+		t.Fatalf("Expected 5 restored notes, got %d", len(notes))
+	}
+	note := notes[0]
+	if note.Title != "it's; note" || note.Body != `line\nwith "quotes"` || !bytes.Equal(note.Attachment, []byte{0x00, 0xff}) || !note.Pinned {
+		t.Errorf("Restored row does not match: %+v", note)
+	}
+	if !note.CreatedAt.Equal(createdAt) {
+		t.Errorf("Expected created_at %v, got %v", createdAt, note.CreatedAt)
+	}
+
+	var indexes int64
+	target.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_notes_title'").Scan(&indexes)
+	if indexes != 1 {
+		t.Error("Expected index to be restored")
+	}
+}
+
+func TestDumpLiterals(t *testing.T) {
+	mysql := &Dumper{dbDriver: "mysql"}
+	if got := mysql.literal([]byte(`a\b'c`), "VARCHAR"); got != `'a\\b''c'` {
+		t.Errorf("Unexpected mysql string literal: %s", got)
+	}
+	if got := mysql.literal([]byte{0x01}, "BLOB"); got != "X'01'" {
+		t.Errorf("Unexpected mysql blob literal: %s", got)
+	}
+
+	postgres := &Dumper{dbDriver: "postgres"}
+	if got := postgres.literal([]byte{0x01}, "BYTEA"); got != `'\x01'` {
+		t.Errorf("Unexpected postgres bytea literal: %s", got)
+	}
+	if got := postgres.literal(true, "BOOL"); got != "TRUE" {
+		t.Errorf("Unexpected postgres bool literal: %s", got)
+	}
+	if got := postgres.literal(nil, "TEXT"); got != "NULL" {
+		t.Errorf("Unexpected NULL literal: %s", got)
+	}
+}
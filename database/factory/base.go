@@ -1,9 +1,16 @@
 package factory
 
-import ()
-
+// Factory is implemented by BaseFactory. Build, Create and CreateMany keep their
+// original signatures, the other methods take per-call overrides
 type Factory[T any] interface {
 	Build() T
 	Create(out *T) error
 	CreateMany(n int) ([]T, error)
+
+	Make(overrides ...func(*T)) T
+	MakeMany(n int, overrides ...func(*T)) []T
+	Raw(overrides ...func(*T)) (map[string]any, error)
+	CreateWith(out *T, overrides ...func(*T)) error
+	CreateManyWith(n int, overrides ...func(*T)) ([]T, error)
+	CreateInBatches(n, batchSize int, overrides ...func(*T)) ([]T, error)
 }
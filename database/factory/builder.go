@@ -1,23 +1,106 @@
 package factory
 
 import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
 	"sync/atomic"
-	"time"
 
 	"github.com/galaplate/core/database"
 	"gorm.io/gorm"
 )
 
+// DefaultBatchSize is the number of rows per INSERT of CreateMany
+const DefaultBatchSize = 100
+
 type BaseFactory[T any] struct {
 	db      *gorm.DB
 	seq     *int64
 	Builder func(seq int64) T
+
+	// states are the named modifiers registered with State, active the applied ones
+	states map[string]func(*T)
+	active []func(*T)
+
+	parents  []Relatable
+	children []childFactory
+
+	// err records an Apply of an undefined state, returned by the Create methods
+	err error
 }
 
-// NewBaseFactory creates a new BaseFactory for a model
+var _ Factory[struct{}] = (*BaseFactory[struct{}])(nil)
+
+// NewBaseFactory creates a new BaseFactory for a model. Factories are usually
+// package variables built before the database is opened, so records are created
+// on database.Connect as it is when they are persisted, unless NewBaseFactoryOn
+// or WithDB set a connection
 func NewBaseFactory[T any](builder func(seq int64) T) *BaseFactory[T] {
 	var s int64 = 0
-	return &BaseFactory[T]{db: database.Connect, seq: &s, Builder: builder}
+	return &BaseFactory[T]{seq: &s, Builder: builder, states: map[string]func(*T){}}
+}
+
+// NewBaseFactoryOn creates a new BaseFactory creating records on db, such as a
+// test transaction
+func NewBaseFactoryOn[T any](db *gorm.DB, builder func(seq int64) T) *BaseFactory[T] {
+	f := NewBaseFactory(builder)
+	f.db = db
+	return f
+}
+
+// WithDB returns a copy of the factory creating records on db, such as a test transaction
+func (f *BaseFactory[T]) WithDB(db *gorm.DB) *BaseFactory[T] {
+	clone := f.clone()
+	clone.db = db
+	return clone
+}
+
+// State registers a named modifier, applied with Apply
+//
+//	users.State("admin", func(u *models.User) { u.Role = "admin" })
+//	admin := users.Apply("admin").Make()
+func (f *BaseFactory[T]) State(name string, fn func(*T)) *BaseFactory[T] {
+	f.states[name] = fn
+	return f
+}
+
+// Apply returns a copy of the factory with the named states applied in order. An
+// undefined state is skipped and recorded, the Create methods return it
+func (f *BaseFactory[T]) Apply(names ...string) *BaseFactory[T] {
+	clone := f.clone()
+	for _, name := range names {
+		fn, ok := f.states[name]
+		if !ok {
+			if clone.err == nil {
+				clone.err = fmt.Errorf("factory state %q is not defined", name)
+			}
+			continue
+		}
+		clone.active = append(clone.active, fn)
+	}
+	return clone
+}
+
+// Err returns the error recorded while configuring the factory, if any
+func (f *BaseFactory[T]) Err() error {
+	return f.err
+}
+
+func (f *BaseFactory[T]) clone() *BaseFactory[T] {
+	clone := *f
+	clone.states = maps.Clone(f.states)
+	clone.active = append([]func(*T){}, f.active...)
+	clone.parents = append([]Relatable{}, f.parents...)
+	clone.children = append([]childFactory{}, f.children...)
+	return &clone
+}
+
+func (f *BaseFactory[T]) conn() *gorm.DB {
+	if f.db != nil {
+		return f.db
+	}
+	return database.Connect
 }
 
 func (f *BaseFactory[T]) nextSeq() int64 {
@@ -26,30 +109,88 @@ func (f *BaseFactory[T]) nextSeq() int64 {
 
 // Build creates an instance but does not persist
 func (f *BaseFactory[T]) Build() T {
-	seq := f.nextSeq()
-	return f.Builder(seq)
+	return f.Make()
+}
+
+// Make creates an instance with the applied states and overrides, without
+// persisting it
+func (f *BaseFactory[T]) Make(overrides ...func(*T)) T {
+	val := f.Builder(f.nextSeq())
+	for _, state := range f.active {
+		state(&val)
+	}
+	for _, override := range overrides {
+		override(&val)
+	}
+	return val
+}
+
+// MakeMany builds n instances without persisting them
+func (f *BaseFactory[T]) MakeMany(n int, overrides ...func(*T)) []T {
+	result := make([]T, 0, n)
+	for range n {
+		result = append(result, f.Make(overrides...))
+	}
+	return result
+}
+
+// Raw builds an instance and returns its column values keyed by column name
+func (f *BaseFactory[T]) Raw(overrides ...func(*T)) (map[string]any, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	val := f.Make(overrides...)
+
+	modelSchema, err := parseSchema(f.conn(), &val)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := map[string]any{}
+	rv := reflect.ValueOf(&val).Elem()
+	for _, field := range modelSchema.Fields {
+		if field.DBName != "" {
+			attributes[field.DBName], _ = field.ValueOf(context.Background(), rv)
+		}
+	}
+	return attributes, nil
 }
 
 // Create builds and persists a new record
 func (f *BaseFactory[T]) Create(out *T) error {
-	val := f.Build()
-	if err := f.db.Create(&val).Error; err != nil {
+	return f.CreateWith(out)
+}
+
+// CreateWith builds and persists a new record with overrides
+func (f *BaseFactory[T]) CreateWith(out *T, overrides ...func(*T)) error {
+	records, err := f.CreateInBatches(1, 1, overrides...)
+	if err != nil {
 		return err
 	}
-	*out = val
+	*out = records[0]
 	return nil
 }
 
-// CreateMany builds and persists multiple records
+// CreateMany builds and persists multiple records with batched inserts
 func (f *BaseFactory[T]) CreateMany(n int) ([]T, error) {
-	result := make([]T, 0, n)
-	for range n {
-		val := f.Build()
-		if err := f.db.Create(&val).Error; err != nil {
-			return nil, err
-		}
-		result = append(result, val)
-		time.Sleep(1 * time.Millisecond)
+	return f.CreateManyWith(n)
+}
+
+// CreateManyWith builds and persists multiple records with overrides
+func (f *BaseFactory[T]) CreateManyWith(n int, overrides ...func(*T)) ([]T, error) {
+	return f.CreateInBatches(n, DefaultBatchSize, overrides...)
+}
+
+// CreateInBatches builds n records and inserts them batchSize rows per statement
+func (f *BaseFactory[T]) CreateInBatches(n, batchSize int, overrides ...func(*T)) ([]T, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	records := f.MakeMany(n, overrides...)
+	if err := f.persist(f.conn(), records, batchSize); err != nil {
+		return nil, err
 	}
-	return result, nil
+	return records, nil
 }
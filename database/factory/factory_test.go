@@ -0,0 +1,192 @@
+package factory
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type testUser struct {
+	ID    uint
+	Name  string
+	Role  string
+	Posts []testPost `gorm:"foreignKey:UserID"`
+}
+
+type testPost struct {
+	ID     uint
+	Title  string
+	UserID uint
+	User   *testUser
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "factory.sqlite")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&testUser{}, &testPost{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	return db
+}
+
+func newUserFactory(db *gorm.DB) *BaseFactory[testUser] {
+	return NewBaseFactory(func(seq int64) testUser {
+		return testUser{Name: fmt.Sprintf("user%d", seq), Role: "member"}
+	}).WithDB(db).State("admin", func(u *testUser) {
+		u.Role = "admin"
+	})
+}
+
+func newPostFactory(db *gorm.DB) *BaseFactory[testPost] {
+	return NewBaseFactoryOn(db, func(seq int64) testPost {
+		return testPost{Title: fmt.Sprintf("post%d", seq)}
+	})
+}
+
+func TestFactoryStatesAndOverrides(t *testing.T) {
+	users := newUserFactory(newTestDB(t))
+
+	admin := users.Apply("admin").Make(func(u *testUser) { u.Name = "root" })
+	if admin.Role != "admin" || admin.Name != "root" {
+		t.Errorf("Expected the state and override applied, got %+v", admin)
+	}
+	if member := users.Make(); member.Role != "member" {
+		t.Errorf("Expected Apply not to change the original factory, got %s", member.Role)
+	}
+
+	// States registered on a copy stay on the copy
+	users.WithDB(nil).State("guest", func(u *testUser) { u.Role = "guest" })
+	if _, ok := users.states["guest"]; ok {
+		t.Error("Expected State on a copy not to change the original factory")
+	}
+
+	raw, err := users.Raw()
+	if err != nil {
+		t.Fatalf("Failed to build raw attributes: %v", err)
+	}
+	if raw["role"] != "member" || raw["name"] == "" {
+		t.Errorf("Expected column values keyed by column name, got %v", raw)
+	}
+
+	var created testUser
+	if err := users.CreateWith(&created, func(u *testUser) { u.Name = "override" }); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if created.ID == 0 || created.Name != "override" {
+		t.Errorf("Expected the override persisted, got %+v", created)
+	}
+}
+
+type testComment struct {
+	ID         uint
+	Body       string
+	TestUserID uint
+}
+
+func TestFactoryForWithoutRelationship(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&testComment{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	comments := NewBaseFactoryOn(db, func(seq int64) testComment {
+		return testComment{Body: fmt.Sprintf("comment%d", seq)}
+	})
+
+	created, err := comments.For(newUserFactory(db)).CreateMany(2)
+	if err != nil {
+		t.Fatalf("Failed to create comments: %v", err)
+	}
+	if created[0].TestUserID == 0 || created[0].TestUserID != created[1].TestUserID {
+		t.Errorf("Expected both comments to reference one user, got %d and %d", created[0].TestUserID, created[1].TestUserID)
+	}
+
+	if _, err := comments.For(newPostFactory(db)).CreateMany(1); err == nil {
+		t.Error("Expected an error without a relationship or foreign key field")
+	}
+}
+
+func TestFactoryUndefinedState(t *testing.T) {
+	db := newTestDB(t)
+	users := newUserFactory(db).Apply("missing")
+
+	if err := users.Err(); err == nil || !strings.Contains(err.Error(), `"missing"`) {
+		t.Errorf("Expected the undefined state recorded, got: %v", err)
+	}
+
+	var user testUser
+	if err := users.Create(&user); err == nil {
+		t.Error("Expected Create to return the undefined state")
+	}
+	if _, err := newPostFactory(db).For(users).CreateMany(1); err == nil {
+		t.Error("Expected a child factory to return its parent's undefined state")
+	}
+
+	var count int64
+	db.Model(&testUser{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected no users created, got %d", count)
+	}
+}
+
+func TestFactoryCreateInBatches(t *testing.T) {
+	db := newTestDB(t)
+
+	var statements int
+	db.Callback().Create().After("gorm:create").Register("test:count", func(*gorm.DB) { statements++ })
+
+	users, err := newUserFactory(db).CreateInBatches(25, 10)
+	if err != nil {
+		t.Fatalf("Failed to create users: %v", err)
+	}
+	if len(users) != 25 || users[24].ID == 0 {
+		t.Errorf("Expected 25 users with IDs, got %d", len(users))
+	}
+	if statements != 3 {
+		t.Errorf("Expected 3 INSERT statements, got %d", statements)
+	}
+}
+
+func TestFactoryRelationships(t *testing.T) {
+	db := newTestDB(t)
+	users := newUserFactory(db)
+	posts := newPostFactory(db)
+
+	created, err := posts.For(users.Apply("admin")).CreateMany(2)
+	if err != nil {
+		t.Fatalf("Failed to create posts: %v", err)
+	}
+	if created[0].UserID == 0 || created[0].UserID != created[1].UserID {
+		t.Errorf("Expected both posts to belong to one user, got %d and %d", created[0].UserID, created[1].UserID)
+	}
+	if created[0].User == nil || created[0].User.Role != "admin" {
+		t.Errorf("Expected the parent set on the post, got %+v", created[0].User)
+	}
+
+	var user testUser
+	if err := users.Has(posts, 3).Create(&user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if len(user.Posts) != 3 || user.Posts[0].UserID != user.ID {
+		t.Errorf("Expected 3 posts linked to the user, got %+v", user.Posts)
+	}
+
+	var count int64
+	db.Model(&testUser{}).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected 2 users, got %d", count)
+	}
+	db.Model(&testPost{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 3 {
+		t.Errorf("Expected 3 posts stored for the user, got %d", count)
+	}
+}
@@ -0,0 +1,234 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Relatable is implemented by every BaseFactory, so factories of different models
+// can be wired together with For and Has
+type Relatable interface {
+	modelType() reflect.Type
+	createRelated(db *gorm.DB, n int, link func(record reflect.Value) error) ([]reflect.Value, error)
+}
+
+// childFactory is a factory creating count children for every record
+type childFactory struct {
+	factory Relatable
+	count   int
+}
+
+// schemaCache caches parsed model schemas across factories
+var schemaCache sync.Map
+
+// For returns a copy of the factory whose records belong to a parent created by
+// the parent factory, through the model's belongs-to relationship or, without
+// one, its <Parent>ID field
+//
+//	posts.For(users).CreateMany(3) // three posts of one new user
+func (f *BaseFactory[T]) For(parent Relatable) *BaseFactory[T] {
+	clone := f.clone()
+	clone.parents = append(clone.parents, parent)
+	return clone
+}
+
+// Has returns a copy of the factory whose records each get n children created by
+// the child factory, through the model's has-many or has-one relationship
+//
+//	users.Has(posts, 3).Create(&user) // user.Posts holds the three posts
+func (f *BaseFactory[T]) Has(child Relatable, n int) *BaseFactory[T] {
+	clone := f.clone()
+	clone.children = append(clone.children, childFactory{factory: child, count: n})
+	return clone
+}
+
+func (f *BaseFactory[T]) modelType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// createRelated creates n records on db, linked to their parent by link first
+func (f *BaseFactory[T]) createRelated(db *gorm.DB, n int, link func(record reflect.Value) error) ([]reflect.Value, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	records := f.MakeMany(n)
+	if link != nil {
+		for i := range records {
+			if err := link(reflect.ValueOf(&records[i]).Elem()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := f.persist(db, records, DefaultBatchSize); err != nil {
+		return nil, err
+	}
+
+	values := make([]reflect.Value, len(records))
+	for i := range records {
+		values[i] = reflect.ValueOf(&records[i]).Elem()
+	}
+	return values, nil
+}
+
+// persist creates the parents of the records, inserts the records in batches and
+// then creates their children
+func (f *BaseFactory[T]) persist(db *gorm.DB, records []T, batchSize int) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	modelSchema, err := parseSchema(db, new(T))
+	if err != nil {
+		return err
+	}
+
+	var omit []string
+	for _, parent := range f.parents {
+		rel := findRelation(modelSchema.Relationships.BelongsTo, parent.modelType())
+		if rel == nil {
+			if err := f.persistForeignKey(db, modelSchema, parent, records); err != nil {
+				return err
+			}
+			continue
+		}
+
+		created, err := parent.createRelated(db, 1, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", parent.modelType().Name(), err)
+		}
+
+		for i := range records {
+			record := reflect.ValueOf(&records[i]).Elem()
+			if err := linkRecords(ctx, rel, created[0], record); err != nil {
+				return err
+			}
+			attach(ctx, rel, record, created)
+		}
+		// The parent exists already, don't let GORM upsert it again
+		omit = append(omit, rel.Name)
+	}
+
+	tx := db
+	if len(omit) > 0 {
+		tx = db.Omit(omit...)
+	}
+	if err := tx.CreateInBatches(&records, batchSize).Error; err != nil {
+		return err
+	}
+
+	for _, child := range f.children {
+		rels := append(append([]*schema.Relationship{}, modelSchema.Relationships.HasMany...), modelSchema.Relationships.HasOne...)
+		rel := findRelation(rels, child.factory.modelType())
+		if rel == nil {
+			return fmt.Errorf("%s has no has-many or has-one relationship with %s", modelSchema.Name, child.factory.modelType().Name())
+		}
+
+		for i := range records {
+			record := reflect.ValueOf(&records[i]).Elem()
+			created, err := child.factory.createRelated(db, child.count, func(childRecord reflect.Value) error {
+				return linkRecords(ctx, rel, record, childRecord)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", child.factory.modelType().Name(), err)
+			}
+			attach(ctx, rel, record, created)
+		}
+	}
+
+	return nil
+}
+
+// persistForeignKey creates the parent of records whose model has no belongs-to
+// relationship with it, and sets their <Parent>ID field to the parent's key
+func (f *BaseFactory[T]) persistForeignKey(db *gorm.DB, modelSchema *schema.Schema, parent Relatable, records []T) error {
+	parentName := parent.modelType().Name()
+	fieldName := strings.ToUpper(parentName[:1]) + parentName[1:] + "ID"
+	foreignKey := modelSchema.LookUpField(fieldName)
+	if foreignKey == nil {
+		return fmt.Errorf("%s has no belongs-to relationship or %s field for %s", modelSchema.Name, fieldName, parentName)
+	}
+
+	created, err := parent.createRelated(db, 1, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", parentName, err)
+	}
+
+	parentSchema, err := parseSchema(db, created[0].Addr().Interface())
+	if err != nil {
+		return err
+	}
+	if parentSchema.PrioritizedPrimaryField == nil {
+		return fmt.Errorf("%s has no primary key", parentName)
+	}
+
+	ctx := context.Background()
+	key, _ := parentSchema.PrioritizedPrimaryField.ValueOf(ctx, created[0])
+	for i := range records {
+		if err := foreignKey.Set(ctx, reflect.ValueOf(&records[i]).Elem(), key); err != nil {
+			return fmt.Errorf("failed to set %s: %w", foreignKey.Name, err)
+		}
+	}
+	return nil
+}
+
+// parseSchema parses a model with the naming strategy of db
+func parseSchema(db *gorm.DB, model any) (*schema.Schema, error) {
+	var namer schema.Namer = schema.NamingStrategy{}
+	if db != nil && db.Config != nil && db.NamingStrategy != nil {
+		namer = db.NamingStrategy
+	}
+	return schema.Parse(model, &schemaCache, namer)
+}
+
+// findRelation returns the relationship whose model has the given type
+func findRelation(relations []*schema.Relationship, modelType reflect.Type) *schema.Relationship {
+	for _, rel := range relations {
+		if rel.FieldSchema != nil && rel.FieldSchema.ModelType == modelType {
+			return rel
+		}
+	}
+	return nil
+}
+
+// linkRecords copies the referenced keys of owner into the foreign keys of record
+func linkRecords(ctx context.Context, rel *schema.Relationship, owner, record reflect.Value) error {
+	for _, ref := range rel.References {
+		var value any = ref.PrimaryValue
+		if ref.PrimaryKey != nil {
+			value, _ = ref.PrimaryKey.ValueOf(ctx, owner)
+		}
+		if err := ref.ForeignKey.Set(ctx, record, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", ref.ForeignKey.Name, err)
+		}
+	}
+	return nil
+}
+
+// attach sets the association field of holder to the related records
+func attach(ctx context.Context, rel *schema.Relationship, holder reflect.Value, related []reflect.Value) {
+	field := rel.Field.ReflectValueOf(ctx, holder)
+
+	switch field.Kind() {
+	case reflect.Slice:
+		for _, value := range related {
+			if field.Type().Elem().Kind() == reflect.Ptr {
+				field.Set(reflect.Append(field, value.Addr()))
+			} else {
+				field.Set(reflect.Append(field, value))
+			}
+		}
+	case reflect.Ptr:
+		field.Set(related[0].Addr())
+	case reflect.Struct:
+		field.Set(related[0])
+	}
+}
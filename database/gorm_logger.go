@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/galaplate/core/config"
+	"github.com/galaplate/core/logger"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// GormLogger writes GORM queries through the framework logger as structured
+// entries, so failed and slow queries end up in storage/logs with everything else
+type GormLogger struct {
+	gormlogger.Config
+}
+
+// NewGormLogger creates a GORM logger adapter for the framework logger
+func NewGormLogger(cfg gormlogger.Config) *GormLogger {
+	return &GormLogger{Config: cfg}
+}
+
+// LogMode returns a copy of the logger with another level
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.LogLevel = level
+	return &clone
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...any) {
+	if l.LogLevel >= gormlogger.Info {
+		logger.Info(fmt.Sprintf(msg, data...), map[string]any{"file": utils.FileWithLineNum()})
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	if l.LogLevel >= gormlogger.Warn {
+		logger.Warn(fmt.Sprintf(msg, data...), map[string]any{"file": utils.FileWithLineNum()})
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...any) {
+	if l.LogLevel >= gormlogger.Error {
+		logger.Error(fmt.Sprintf(msg, data...), map[string]any{"file": utils.FileWithLineNum()})
+	}
+}
+
+// Trace logs a query with its duration and affected rows. Errors are logged at
+// the error level, queries slower than SlowThreshold at the warn level and every
+// other query at the info level
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.LogLevel <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	failed := err != nil && l.LogLevel >= gormlogger.Error &&
+		(!errors.Is(err, gorm.ErrRecordNotFound) || !l.IgnoreRecordNotFoundError)
+	slow := l.SlowThreshold != 0 && elapsed > l.SlowThreshold && l.LogLevel >= gormlogger.Warn
+
+	if !failed && !slow && l.LogLevel < gormlogger.Info {
+		return
+	}
+
+	sql, rows := fc()
+	fields := map[string]any{
+		"sql":         sql,
+		"duration_ms": float64(elapsed.Microseconds()) / 1000,
+		"file":        utils.FileWithLineNum(),
+	}
+	if rows >= 0 {
+		fields["rows"] = rows
+	}
+
+	switch {
+	case failed:
+		fields["error"] = err.Error()
+		logger.Error("database@query", fields)
+	case slow:
+		fields["slow_threshold_ms"] = l.SlowThreshold.Milliseconds()
+		logger.Warn("database@slow_query", fields)
+	default:
+		logger.Info("database@query", fields)
+	}
+}
+
+// ParamsFilter keeps bound values out of the logged SQL when ParameterizedQueries is set
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	if l.ParameterizedQueries {
+		return sql, nil
+	}
+	return sql, params
+}
+
+// loggerFromConfig builds the GORM logger from database.log. Queries are written
+// through the framework logger unless the stdout channel is chosen:
+//
+//	log:
+//	  level: warn           # silent, error, warn or info
+//	  slow_threshold: 200ms
+//	  parameterized: true   # log placeholders instead of bound values
+//	  channel: stdout       # print to stdout instead of storage/logs
+//	  colorful: true        # colors on the stdout channel
+func loggerFromConfig() gormlogger.Interface {
+	cfg := gormlogger.Config{
+		SlowThreshold:             time.Second,
+		LogLevel:                  parseGormLogLevel(config.ConfigString("database.log.level")),
+		IgnoreRecordNotFoundError: true,
+		ParameterizedQueries:      configBoolDefault("database.log.parameterized", true),
+	}
+	if config.GetGlobal().Has("database.log.slow_threshold") {
+		cfg.SlowThreshold = parseDuration(config.ConfigString("database.log.slow_threshold"))
+	}
+
+	if strings.ToLower(config.ConfigString("database.log.channel")) == "stdout" {
+		cfg.Colorful = configBoolDefault("database.log.colorful", true)
+		return gormlogger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), cfg)
+	}
+
+	return NewGormLogger(cfg)
+}
+
+// parseGormLogLevel maps a level name to a GORM log level, defaulting to warn
+func parseGormLogLevel(level string) gormlogger.LogLevel {
+	switch strings.ToLower(level) {
+	case "silent":
+		return gormlogger.Silent
+	case "error":
+		return gormlogger.Error
+	case "info":
+		return gormlogger.Info
+	default:
+		return gormlogger.Warn
+	}
+}
+
+// configBoolDefault reads a boolean setting, returning fallback when it is unset
+func configBoolDefault(key string, fallback bool) bool {
+	if !config.GetGlobal().Has(key) {
+		return fallback
+	}
+	return config.ConfigBool(key)
+}
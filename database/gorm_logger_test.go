@@ -0,0 +1,108 @@
+package database
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galaplate/core/config"
+	"github.com/galaplate/core/logger"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLoggerWritesStructuredEntries(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	if err := logger.SetFile(logFile); err != nil {
+		t.Fatalf("Failed to set log file: %v", err)
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: NewGormLogger(gormlogger.Config{
+			SlowThreshold:        time.Nanosecond,
+			LogLevel:             gormlogger.Warn,
+			ParameterizedQueries: true,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	db.Exec("INSERT INTO users (name) VALUES (?)", "secret")
+	db.Exec("SELECT * FROM missing")
+
+	content, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	entries := map[string][]map[string]any{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var entry struct {
+			Message string         `json:"message"`
+			Data    map[string]any `json:"additional_info"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Expected JSON log lines, got %s", line)
+		}
+		entries[entry.Message] = append(entries[entry.Message], entry.Data)
+	}
+
+	slow := entries["database@slow_query"]
+	if len(slow) != 2 {
+		t.Fatalf("Expected 2 slow queries, got %d", len(slow))
+	}
+	if sql := slow[1]["sql"]; sql != "INSERT INTO users (name) VALUES (?)" {
+		t.Errorf("Expected parameterized SQL, got %v", sql)
+	}
+	if _, ok := slow[1]["duration_ms"]; !ok || slow[1]["rows"] != float64(1) {
+		t.Errorf("Expected duration and rows, got %v", slow[1])
+	}
+
+	failed := entries["database@query"]
+	if len(failed) != 1 || !strings.Contains(failed[0]["error"].(string), "no such table") {
+		t.Errorf("Expected the failed query logged with its error, got %v", failed)
+	}
+}
+
+func TestConfiguredLoggerWarnsOnSlowQueries(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	if err := logger.SetFile(logFile); err != nil {
+		t.Fatalf("Failed to set log file: %v", err)
+	}
+
+	config.GetGlobal().Set("database.log.slow_threshold", "1ns")
+	t.Cleanup(func() { config.GetGlobal().Set("database.log.slow_threshold", "1s") })
+
+	gormLogger := loggerFromConfig()
+	if _, ok := gormLogger.(*GormLogger); !ok {
+		t.Fatalf("Expected the framework logger adapter by default, got %T", gormLogger)
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormLogger})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	db.Exec("SELECT 1")
+
+	content, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	var entry struct {
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(content))), &entry); err != nil {
+		t.Fatalf("Expected one JSON log line, got %s", content)
+	}
+	if entry.Level != "WARN" || entry.Message != "database@slow_query" {
+		t.Errorf("Expected the slow query logged as a warning, got %+v", entry)
+	}
+}
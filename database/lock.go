@@ -0,0 +1,177 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultMigrationLockTimeout is how long Migrator.Up waits for another migrator
+const DefaultMigrationLockTimeout = 60 * time.Second
+
+const (
+	migrationLockName = "galaplate_migrations"
+
+	// migrationLockKey is the pg_advisory_lock key, "galaplat" in hex
+	migrationLockKey int64 = 0x67616c61706c6174
+
+	migrationLockPollInterval = 250 * time.Millisecond
+)
+
+// ErrMigrationLocked is returned when the migration lock isn't acquired within the timeout
+var ErrMigrationLocked = errors.New("another migrator is running")
+
+// SetLockTimeout sets how long Up waits for the migration lock
+func (m *Migrator) SetLockTimeout(timeout time.Duration) *Migrator {
+	m.lockTimeout = timeout
+	return m
+}
+
+// acquireLock takes a driver-appropriate lock so only one migrator runs at a time.
+// The returned function releases it
+func (m *Migrator) acquireLock() (func(), error) {
+	timeout := m.lockTimeout
+	if timeout <= 0 {
+		timeout = DefaultMigrationLockTimeout
+	}
+
+	var (
+		release func()
+		err     error
+	)
+
+	switch m.schema.dbDriver {
+	case "postgres":
+		release, err = m.acquireSessionLock(timeout,
+			"SELECT pg_try_advisory_lock(?)",
+			"SELECT pg_advisory_unlock(?)",
+			migrationLockKey,
+		)
+	case "mysql":
+		release, err = m.acquireSessionLock(timeout,
+			"SELECT GET_LOCK(CONCAT(DATABASE(), '.', ?), 0) = 1",
+			"SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.', ?))",
+			migrationLockName,
+		)
+	case "sqlite":
+		release, err = m.acquireLockRow(timeout)
+	default:
+		return func() {}, nil
+	}
+
+	if errors.Is(err, ErrMigrationLocked) {
+		return nil, fmt.Errorf("could not acquire the migration lock within %s, wait for the other deploy to finish or raise the timeout: %w", timeout, err)
+	}
+
+	return release, err
+}
+
+// acquireSessionLock polls a session-scoped lock (pg_advisory_lock, GET_LOCK) on a
+// dedicated connection, which is kept open until the lock is released. The
+// migrations run on other connections, so the pool needs room for at least two
+func (m *Migrator) acquireSessionLock(timeout time.Duration, lockSQL, unlockSQL string, key any) (func(), error) {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	if sqlDB.Stats().MaxOpenConnections == 1 {
+		return nil, errors.New("the migration lock holds a connection of its own, set the connection's pool_size to at least 2")
+	}
+
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migration lock connection: %w", err)
+	}
+
+	acquired, err := pollLock(timeout, func() (bool, error) {
+		var ok bool
+		err := conn.QueryRowContext(ctx, rebind(m.schema.dbDriver, lockSQL), key).Scan(&ok)
+		return ok, err
+	})
+	if err != nil || !acquired {
+		conn.Close()
+		if err == nil {
+			err = ErrMigrationLocked
+		}
+		return nil, err
+	}
+
+	return func() {
+		conn.ExecContext(ctx, rebind(m.schema.dbDriver, unlockSQL), key)
+		conn.Close()
+	}, nil
+}
+
+// acquireLockRow inserts the single row of the migrations_lock table. SQLite has no
+// advisory locks, so the primary key makes a second insert fail while the row exists
+func (m *Migrator) acquireLockRow(timeout time.Duration) (func(), error) {
+	if !m.schema.HasTable("migrations_lock") {
+		if err := m.schema.Create("migrations_lock", func(table *Blueprint) {
+			table.Integer("id").NotNullable()
+			table.String("owner").NotNullable()
+			table.Timestamp("acquired_at").NotNullable().Default("CURRENT_TIMESTAMP")
+			table.Primary([]string{"id"})
+		}); err != nil && !m.schema.HasTable("migrations_lock") {
+			return nil, fmt.Errorf("failed to create migrations_lock table: %w", err)
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s:%d:%d", hostname, os.Getpid(), time.Now().UnixNano())
+
+	acquired, err := pollLock(timeout, func() (bool, error) {
+		result := m.db.Exec("INSERT OR IGNORE INTO migrations_lock (id, owner) VALUES (1, ?)", owner)
+		return result.RowsAffected == 1, result.Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		var holder string
+		m.db.Raw("SELECT owner FROM migrations_lock WHERE id = 1").Scan(&holder)
+		return nil, fmt.Errorf("%w (held by %s, delete the migrations_lock row if that process is gone)", ErrMigrationLocked, holder)
+	}
+
+	return func() {
+		m.db.Exec("DELETE FROM migrations_lock WHERE id = 1 AND owner = ?", owner)
+	}, nil
+}
+
+// pollLock retries try until it succeeds or the timeout passes
+func pollLock(timeout time.Duration, try func() (bool, error)) (bool, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		acquired, err := try()
+		if err != nil || acquired {
+			return acquired, err
+		}
+		if time.Now().After(deadline) {
+			return false, nil
+		}
+		time.Sleep(migrationLockPollInterval)
+	}
+}
+
+// rebind converts ? placeholders for drivers using numbered parameters
+func rebind(driver, query string) string {
+	if driver != "postgres" {
+		return query
+	}
+
+	var out []byte
+	n := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] == '?' {
+			n++
+			out = append(out, fmt.Sprintf("$%d", n)...)
+			continue
+		}
+		out = append(out, query[i])
+	}
+	return string(out)
+}
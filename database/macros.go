@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+)
+
+// MacroFunc defines a reusable group of Blueprint calls
+type MacroFunc func(table *Blueprint, args ...any)
+
+// ColumnType describes a custom column type that can be used with Blueprint.AddColumn
+type ColumnType struct {
+	// Name is the type identifier stored on Column.Type
+	Name string
+
+	// Drivers maps a driver ("mysql", "postgres", "sqlite") to its SQL type
+	Drivers map[string]string
+
+	// Resolve builds the SQL type from the column definition, taking precedence
+	// over Drivers. Return an empty string when the driver isn't supported
+	Resolve func(col Column, driver string) string
+
+	// Numeric allows the UNSIGNED modifier on MySQL
+	Numeric bool
+}
+
+// MacroRegistry holds all registered Blueprint macros
+var MacroRegistry = map[string]MacroFunc{}
+
+// ColumnTypeRegistry holds all registered custom column types
+var ColumnTypeRegistry = map[string]ColumnType{}
+
+// Macro registers a Blueprint macro that can be invoked with Blueprint.Macro
+//
+//	database.Macro("auditable", func(table *database.Blueprint, args ...any) {
+//		table.ForeignID("created_by").Nullable()
+//		table.ForeignID("updated_by").Nullable()
+//	})
+func Macro(name string, fn MacroFunc) {
+	MacroRegistry[name] = fn
+}
+
+// HasMacro checks if a macro is registered
+func HasMacro(name string) bool {
+	_, ok := MacroRegistry[name]
+	return ok
+}
+
+// RegisterColumnType registers a custom column type
+//
+//	database.RegisterColumnType(database.ColumnType{
+//		Name:    "citext",
+//		Drivers: map[string]string{"postgres": "CITEXT"},
+//	})
+func RegisterColumnType(columnType ColumnType) {
+	ColumnTypeRegistry[columnType.Name] = columnType
+}
+
+// Macro runs a registered macro against the blueprint
+func (b *Blueprint) Macro(name string, args ...any) *Blueprint {
+	fn, ok := MacroRegistry[name]
+	if !ok {
+		b.addError(fmt.Errorf("blueprint macro %q is not registered", name))
+		return b
+	}
+	fn(b, args...)
+	return b
+}
+
+// AddColumn creates a column of any built-in or registered custom type.
+// The optional length is available to ColumnType.Resolve as col.Length
+func (b *Blueprint) AddColumn(columnType, name string, length ...int) *Blueprint {
+	col := Column{
+		Name:     name,
+		Type:     columnType,
+		Nullable: true,
+	}
+	if len(length) > 0 {
+		col.Length = length[0]
+	}
+	b.addOrUpdateColumn(col)
+	return b
+}
+
+// customColumnType resolves a registered custom column type for the blueprint driver
+func (b *Blueprint) customColumnType(col Column) (string, bool) {
+	columnType, ok := ColumnTypeRegistry[col.Type]
+	if !ok {
+		return "", false
+	}
+
+	var dbType string
+	if columnType.Resolve != nil {
+		dbType = columnType.Resolve(col, b.dbDriver)
+	} else {
+		dbType = columnType.Drivers[b.dbDriver]
+	}
+
+	if dbType == "" {
+		b.addError(fmt.Errorf("column type %q is not supported on %s (column %s)", col.Type, b.dbDriver, col.Name))
+		return col.Type, true
+	}
+
+	if columnType.Numeric && col.Unsigned && b.dbDriver == "mysql" {
+		dbType += " UNSIGNED"
+	}
+
+	return dbType, true
+}
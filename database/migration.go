@@ -18,6 +18,10 @@ type Migration interface {
 type BaseMigration struct {
 	Name      string
 	Timestamp int64
+
+	// Connection is the database.connections entry the migration runs on,
+	// the default connection when empty
+	Connection string
 }
 
 func (m *BaseMigration) GetName() string {
@@ -32,11 +36,48 @@ func (m *BaseMigration) GetFileName() string {
 	return fmt.Sprintf("%d_%s", m.Timestamp, m.Name)
 }
 
+func (m *BaseMigration) GetConnection() string {
+	return m.Connection
+}
+
+// ConnectionMigration is implemented by migrations that target a named connection.
+// The migrations table itself always lives on the default connection
+type ConnectionMigration interface {
+	GetConnection() string
+}
+
+// TransactionalMigration is implemented by migrations that choose explicitly
+// whether the migrator wraps them in a transaction. Without it, migrations run
+// in one on every driver. MySQL auto-commits DDL, so DDL-heavy migrations there
+// can embed WithoutTransaction
+type TransactionalMigration interface {
+	WithinTransaction() bool
+}
+
+// WithoutTransaction can be embedded in a migration to run it outside a
+// transaction, e.g. for PostgreSQL CREATE INDEX CONCURRENTLY
+type WithoutTransaction struct{}
+
+// WithinTransaction implements TransactionalMigration
+func (WithoutTransaction) WithinTransaction() bool {
+	return false
+}
+
+// WithTransaction can be embedded in a migration to state explicitly that it
+// runs in a transaction, which is also the default
+type WithTransaction struct{}
+
+// WithinTransaction implements TransactionalMigration
+func (WithTransaction) WithinTransaction() bool {
+	return true
+}
+
 // MigrationInfo holds metadata about a migration
 type MigrationInfo struct {
 	ID        int64     `json:"id"`
 	Migration string    `json:"migration"`
 	Batch     int       `json:"batch"`
+	Checksum  string    `json:"checksum"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
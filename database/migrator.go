@@ -2,28 +2,39 @@ package database
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/galaplate/core/config"
+	"github.com/galaplate/core/logger"
 	"github.com/galaplate/core/supports"
 	"gorm.io/gorm"
 )
 
 // Migrator handles running migrations
 type Migrator struct {
-	db       *gorm.DB
-	schema   *Schema
-	registry *MigrationRegistry
+	db          *gorm.DB
+	schema      *Schema
+	registry    *MigrationRegistry
+	lockTimeout time.Duration
+
+	// connection is the name of the connection holding the migrations table
+	connection string
+
+	// schemaDumpPath overrides SchemaDumpPath for the driver
+	schemaDumpPath string
 }
 
 // NewMigrator creates a new migrator instance
 func NewMigrator() *Migrator {
 	return &Migrator{
-		db:       Connect,
-		schema:   NewSchema(),
-		registry: DefaultRegistry,
+		db:          Connect,
+		schema:      NewSchema(),
+		registry:    DefaultRegistry,
+		connection:  config.ConfigString("database.default"),
+		lockTimeout: time.Duration(config.ConfigInt("database.migrations.lock_timeout")) * time.Second,
 	}
 }
 
@@ -66,13 +77,20 @@ func (m *Migrator) enableForeignKeyChecks() error {
 // CreateMigrationsTable creates the migrations table if it doesn't exist
 func (m *Migrator) CreateMigrationsTable() error {
 	if m.schema.HasTable("migrations") {
-		return nil
+		// Tables created before checksums were recorded get the column added
+		if m.schema.HasColumn("migrations", "checksum") {
+			return nil
+		}
+		return m.schema.Table("migrations", func(table *Blueprint) {
+			table.String("checksum", 64).Nullable()
+		})
 	}
 
 	return m.schema.Create("migrations", func(table *Blueprint) {
 		table.ID()
 		table.String("migration", 255).NotNullable()
 		table.Integer("batch").NotNullable()
+		table.String("checksum", 64).Nullable()
 		table.Timestamp("created_at").NotNullable().Default("CURRENT_TIMESTAMP")
 	})
 }
@@ -171,6 +189,20 @@ func (m *Migrator) GetMigrationsForRollback() ([]Migration, error) {
 
 // Up runs all pending migrations
 func (m *Migrator) Up() error {
+	// Hold the lock for the whole run so concurrent deploys don't apply the
+	// same migration twice or race on the batch number
+	release, err := m.acquireLock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// An empty database starts from the schema dump, if any, so only
+	// migrations newer than the dump run
+	if _, err := m.loadSchemaDumpIfEmpty(); err != nil {
+		return err
+	}
+
 	if err := m.CreateMigrationsTable(); err != nil {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
@@ -195,38 +227,138 @@ func (m *Migrator) Up() error {
 	for _, migration := range pending {
 		fmt.Printf("Migrating: %s", migration.GetFileName())
 
-		// Start transaction
-		tx := m.db.Begin()
-
-		// Create schema with transaction
-		txSchema := &Schema{db: tx, dbDriver: m.schema.dbDriver}
-
-		// Run the migration
-		if err := migration.Up(txSchema); err != nil {
-			tx.Rollback()
+		checksum, err := m.Checksum(migration)
+		if err != nil {
 			fmt.Printf(" ❌\n")
-			return fmt.Errorf("migration %s failed: %w", migration.GetName(), err)
+			return err
 		}
 
 		// Record the migration
 		migrationRecord := MigrationInfo{
 			Migration: migration.GetName(),
 			Batch:     newBatch,
+			Checksum:  checksum,
 			CreatedAt: time.Now(),
 		}
 
-		if err := tx.Table("migrations").Create(&migrationRecord).Error; err != nil {
-			tx.Rollback()
+		if err := m.execute(migration, "migration", migration.Up, func(db *gorm.DB) error {
+			return db.Table("migrations").Create(&migrationRecord).Error
+		}); err != nil {
 			fmt.Printf(" ❌\n")
-			return fmt.Errorf("failed to record migration %s: %w", migration.GetName(), err)
+			return err
 		}
 
-		tx.Commit()
 		fmt.Printf(" DONE\n")
 	}
 	return nil
 }
 
+// connectionFor returns the schema a migration runs on: the migrator's own
+// connection, or the named connection the migration declares
+func (m *Migrator) connectionFor(migration Migration) (*Schema, error) {
+	if target, ok := migration.(ConnectionMigration); ok {
+		if name := target.GetConnection(); name != "" && name != m.connection {
+			schema, err := NewConnectionSchema(name)
+			if err != nil {
+				return nil, fmt.Errorf("migration %s: %w", migration.GetName(), err)
+			}
+			return schema, nil
+		}
+	}
+	return &Schema{db: m.db, dbDriver: m.schema.dbDriver}, nil
+}
+
+// withinTransaction reports whether a migration runs inside a transaction
+func (m *Migrator) withinTransaction(migration Migration) bool {
+	if transactional, ok := migration.(TransactionalMigration); ok {
+		return transactional.WithinTransaction()
+	}
+	return true
+}
+
+// execute runs one direction of a migration and updates the migrations table
+// through record, inside a transaction unless the migration opted out. When the
+// migration targets another connection, the migrations table is updated once
+// its transaction is committed
+func (m *Migrator) execute(migration Migration, action string, run func(schema *Schema) error, record func(db *gorm.DB) error) error {
+	target, err := m.connectionFor(migration)
+	if err != nil {
+		return err
+	}
+	sameConnection := target.db == m.db
+
+	if !m.withinTransaction(migration) {
+		if err := run(target); err != nil {
+			return m.partialFailure(migration, action, target, err)
+		}
+		if err := record(m.db); err != nil {
+			return fmt.Errorf("%s %s was applied but updating the migrations table failed: %w", action, migration.GetName(), err)
+		}
+		return nil
+	}
+
+	tx := target.db.Begin()
+	txSchema := &Schema{db: tx, dbDriver: target.dbDriver, inTransaction: true}
+
+	if err := run(txSchema); err != nil {
+		tx.Rollback()
+		return m.partialFailure(migration, action, txSchema, err)
+	}
+
+	if sameConnection {
+		if err := record(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update the migrations table for %s %s: %w", action, migration.GetName(), err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit %s %s: %w", action, migration.GetName(), err)
+	}
+
+	if !sameConnection {
+		if err := record(m.db); err != nil {
+			return fmt.Errorf("%s %s was applied but updating the migrations table failed: %w", action, migration.GetName(), err)
+		}
+	}
+
+	if err := m.runDeferred(target.db, txSchema); err != nil {
+		return fmt.Errorf("%s %s committed but a statement run outside the transaction failed: %w", action, migration.GetName(), err)
+	}
+
+	return nil
+}
+
+// partialFailure builds the error for a failed migration. When statements were
+// applied without a transaction to undo them (MySQL DDL auto-commits even inside
+// one), it warns and lists exactly which statements remain applied
+func (m *Migrator) partialFailure(migration Migration, action string, schema *Schema, err error) error {
+	rolledBack := schema.inTransaction && schema.dbDriver != "mysql"
+	if rolledBack || len(schema.applied) == 0 {
+		return fmt.Errorf("%s %s failed: %w", action, migration.GetName(), err)
+	}
+
+	logger.Warn("migrator@partialFailure", map[string]any{
+		"migration": migration.GetName(),
+		"applied":   schema.applied,
+		"error":     err.Error(),
+	})
+
+	return fmt.Errorf("%s %s failed midway, %d statement(s) were applied and not rolled back:\n  %s\n%w",
+		action, migration.GetName(), len(schema.applied), strings.Join(schema.applied, "\n  "), err)
+}
+
+// runDeferred executes statements a migration queued to run outside its
+// transaction, such as PostgreSQL CREATE INDEX CONCURRENTLY
+func (m *Migrator) runDeferred(db *gorm.DB, txSchema *Schema) error {
+	for _, statement := range txSchema.deferred {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Down rolls back the last batch of migrations
 func (m *Migrator) Down() error {
 	if err := m.CreateMigrationsTable(); err != nil {
@@ -246,27 +378,13 @@ func (m *Migrator) Down() error {
 	for _, migration := range migrations {
 		fmt.Printf("Rolling back: %s", migration.GetFileName())
 
-		// Start transaction
-		tx := m.db.Begin()
-
-		// Create schema with transaction
-		txSchema := &Schema{db: tx, dbDriver: m.schema.dbDriver}
-
-		// Run the rollback
-		if err := migration.Down(txSchema); err != nil {
-			tx.Rollback()
-			fmt.Printf(" ❌\n")
-			return fmt.Errorf("rollback of %s failed: %w", migration.GetName(), err)
-		}
-
-		// Remove the migration record
-		if err := tx.Table("migrations").Where("migration = ?", migration.GetName()).Delete(&MigrationInfo{}).Error; err != nil {
-			tx.Rollback()
+		if err := m.execute(migration, "rollback of", migration.Down, func(db *gorm.DB) error {
+			return db.Table("migrations").Where("migration = ?", migration.GetName()).Delete(&MigrationInfo{}).Error
+		}); err != nil {
 			fmt.Printf(" ❌\n")
-			return fmt.Errorf("failed to remove migration record %s: %w", migration.GetName(), err)
+			return err
 		}
 
-		tx.Commit()
 		fmt.Printf(" DONE\n")
 	}
 	return nil
@@ -274,18 +392,30 @@ func (m *Migrator) Down() error {
 
 // Status shows the migration status
 func (m *Migrator) Status() error {
+	_, err := m.StatusReport()
+	return err
+}
+
+// StatusReport shows the migration status and returns the drift it was computed
+// from, so callers can act on it without checking the database again
+func (m *Migrator) StatusReport() (*DriftReport, error) {
 	if err := m.CreateMigrationsTable(); err != nil {
-		return fmt.Errorf("failed to create migrations table: %w", err)
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
 	}
 
 	ran, err := m.GetRanMigrations()
 	if err != nil {
-		return fmt.Errorf("failed to get ran migrations: %w", err)
+		return nil, fmt.Errorf("failed to get ran migrations: %w", err)
 	}
 
 	pending, err := m.GetPendingMigrations()
 	if err != nil {
-		return fmt.Errorf("failed to get pending migrations: %w", err)
+		return nil, fmt.Errorf("failed to get pending migrations: %w", err)
+	}
+
+	drift, err := m.Drift()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check migration drift: %w", err)
 	}
 
 	ranMap := make(map[string]bool)
@@ -299,18 +429,32 @@ func (m *Migrator) Status() error {
 	fmt.Printf("%-50s %s\n", strings.Repeat("-", 50), strings.Repeat("-", 10))
 
 	for _, migration := range allMigrations {
+		name := migration.GetName()
 		status := "Pending"
-		if ranMap[migration.GetName()] {
+		switch {
+		case slices.Contains(drift.Modified, name):
+			status = "Modified"
+		case ranMap[name]:
 			status = "Ran"
+		case slices.Contains(drift.OutOfOrder, name):
+			status = "Pending (out of order)"
 		}
-		fmt.Printf("%-50s %s\n", migration.GetName(), status)
+		fmt.Printf("%-50s %s\n", name, status)
+	}
+
+	for _, name := range drift.Missing {
+		fmt.Printf("%-50s %s\n", name, "Missing")
 	}
 
 	fmt.Printf("\nTotal migrations: %d\n", len(allMigrations))
 	fmt.Printf("Ran: %d\n", len(ran))
 	fmt.Printf("Pending: %d\n", len(pending))
 
-	return nil
+	if drift.HasDrift() {
+		fmt.Printf("\n⚠️  %s\n", drift.Error())
+	}
+
+	return drift, nil
 }
 
 // Reset rolls back all migrations
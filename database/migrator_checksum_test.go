@@ -0,0 +1,169 @@
+package database
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type checksumMigration struct {
+	BaseMigration
+	up func(schema *Schema) error
+}
+
+func (m *checksumMigration) Up(schema *Schema) error {
+	return m.up(schema)
+}
+
+func (m *checksumMigration) Down(schema *Schema) error {
+	return nil
+}
+
+func newChecksumMigrator(t *testing.T) (*Migrator, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.sqlite")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+
+	return &Migrator{
+		db:       db,
+		schema:   &Schema{db: db, dbDriver: "sqlite"},
+		registry: &MigrationRegistry{},
+	}, db
+}
+
+func createPostsTable(columns ...string) func(schema *Schema) error {
+	return func(schema *Schema) error {
+		return schema.Create("posts", func(table *Blueprint) {
+			table.ID()
+			for _, column := range columns {
+				table.String(column)
+			}
+		})
+	}
+}
+
+func TestChecksumIsTakenFromDryRun(t *testing.T) {
+	migrator, _ := newChecksumMigrator(t)
+
+	migration := &checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_posts_table", Timestamp: 1},
+		up:            createPostsTable("title"),
+	}
+
+	first, err := migrator.Checksum(migration)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if migrator.schema.HasTable("posts") {
+		t.Fatal("Checksum must not execute the migration")
+	}
+
+	second, _ := migrator.Checksum(migration)
+	if first != second || len(first) != 64 {
+		t.Errorf("Expected a stable SHA-256 checksum, got %q and %q", first, second)
+	}
+
+	migration.up = createPostsTable("title", "body")
+	if changed, _ := migrator.Checksum(migration); changed == first {
+		t.Error("Expected checksum to change when the generated SQL changes")
+	}
+}
+
+func TestDriftDetection(t *testing.T) {
+	migrator, db := newChecksumMigrator(t)
+
+	posts := &checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_posts_table", Timestamp: 2},
+		up:            createPostsTable("title"),
+	}
+	removed := &checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_tags_table", Timestamp: 3},
+		up: func(schema *Schema) error {
+			return schema.Create("tags", func(table *Blueprint) {
+				table.ID()
+			})
+		},
+	}
+	migrator.registry.Register(posts)
+	migrator.registry.Register(removed)
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	var checksum string
+	db.Table("migrations").Where("migration = ?", "2_create_posts_table").Pluck("checksum", &checksum)
+	if checksum == "" {
+		t.Fatal("Expected checksum to be recorded")
+	}
+
+	report, err := migrator.Drift()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.HasDrift() {
+		t.Fatalf("Expected no drift, got: %v", report)
+	}
+
+	// Edit a ran migration, unregister another and add an older pending one
+	posts.up = createPostsTable("title", "body")
+	migrator.registry = &MigrationRegistry{}
+	migrator.registry.Register(posts)
+	migrator.registry.Register(&checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_users_table", Timestamp: 1},
+		up:            createPostsTable(),
+	})
+
+	report, err = migrator.Drift()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(report.Modified, []string{"2_create_posts_table"}) {
+		t.Errorf("Expected modified migration, got: %v", report.Modified)
+	}
+	if !slices.Equal(report.Missing, []string{"3_create_tags_table"}) {
+		t.Errorf("Expected missing migration, got: %v", report.Missing)
+	}
+	if !slices.Equal(report.OutOfOrder, []string{"1_create_users_table"}) {
+		t.Errorf("Expected out-of-order migration, got: %v", report.OutOfOrder)
+	}
+
+	// Status prints from the same report it returns for db:status --strict
+	statusReport, err := migrator.StatusReport()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !statusReport.HasDrift() || !slices.Equal(statusReport.Modified, report.Modified) {
+		t.Errorf("Expected status to report the drift, got: %v", statusReport)
+	}
+}
+
+func TestDriftSkipsLegacyRecords(t *testing.T) {
+	migrator, db := newChecksumMigrator(t)
+
+	// A migrations table created before checksums existed
+	if err := db.Exec("CREATE TABLE migrations (id INTEGER PRIMARY KEY AUTOINCREMENT, migration VARCHAR(255) NOT NULL, batch INTEGER NOT NULL, created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)").Error; err != nil {
+		t.Fatalf("Failed to create legacy table: %v", err)
+	}
+	db.Exec("INSERT INTO migrations (migration, batch) VALUES ('1_create_posts_table', 1)")
+
+	migrator.registry.Register(&checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_posts_table", Timestamp: 1},
+		up:            createPostsTable("title"),
+	})
+
+	report, err := migrator.Drift()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.HasDrift() {
+		t.Errorf("Legacy records without a checksum should not be flagged, got: %v", report)
+	}
+	if !migrator.schema.HasColumn("migrations", "checksum") {
+		t.Error("Expected checksum column to be added to the legacy migrations table")
+	}
+}
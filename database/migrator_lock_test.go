@@ -0,0 +1,61 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMigrationLockOnSQLite(t *testing.T) {
+	migrator, db := newChecksumMigrator(t)
+	migrator.SetLockTimeout(300 * time.Millisecond)
+
+	release, err := migrator.acquireLock()
+	if err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+
+	other := &Migrator{db: db, schema: migrator.schema, registry: &MigrationRegistry{}}
+	other.SetLockTimeout(300 * time.Millisecond)
+
+	if err := other.Up(); !errors.Is(err, ErrMigrationLocked) {
+		t.Fatalf("Expected ErrMigrationLocked while another migrator holds the lock, got: %v", err)
+	}
+
+	release()
+
+	if err := other.Up(); err != nil {
+		t.Fatalf("Expected Up to run once the lock is released, got: %v", err)
+	}
+
+	var count int64
+	db.Raw("SELECT COUNT(*) FROM migrations_lock").Scan(&count)
+	if count != 0 {
+		t.Errorf("Expected lock row to be released after Up, found %d", count)
+	}
+}
+
+func TestSessionLockNeedsTwoConnections(t *testing.T) {
+	migrator, db := newChecksumMigrator(t)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	_, err = migrator.acquireSessionLock(time.Second, "SELECT 1", "SELECT 1", 0)
+	if err == nil || !strings.Contains(err.Error(), "pool_size") {
+		t.Fatalf("Expected a pool_size error with a single connection pool, got: %v", err)
+	}
+}
+
+func TestRebindPlaceholders(t *testing.T) {
+	if got := rebind("postgres", "SELECT pg_try_advisory_lock(?)"); got != "SELECT pg_try_advisory_lock($1)" {
+		t.Errorf("Unexpected postgres query: %s", got)
+	}
+	if got := rebind("mysql", "SELECT GET_LOCK(?, 0)"); got != "SELECT GET_LOCK(?, 0)" {
+		t.Errorf("Unexpected mysql query: %s", got)
+	}
+}
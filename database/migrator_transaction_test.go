@@ -0,0 +1,85 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+type concurrentIndexMigration struct {
+	WithoutTransaction
+	checksumMigration
+}
+
+func TestMigrationTransactionMode(t *testing.T) {
+	migrator, _ := newChecksumMigrator(t)
+
+	plain := &checksumMigration{up: createPostsTable()}
+	if !migrator.withinTransaction(plain) {
+		t.Error("Expected migrations to run in a transaction by default")
+	}
+	if migrator.withinTransaction(&concurrentIndexMigration{}) {
+		t.Error("Expected WithoutTransaction to opt out")
+	}
+	if !migrator.withinTransaction(&struct {
+		WithTransaction
+		checksumMigration
+	}{}) {
+		t.Error("Expected WithTransaction to opt in")
+	}
+}
+
+func failingPostsMigration(timestamp int64) checksumMigration {
+	return checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_posts_table", Timestamp: timestamp},
+		up: func(schema *Schema) error {
+			if err := createPostsTable("title")(schema); err != nil {
+				return err
+			}
+			return schema.Table("missing", func(table *Blueprint) {
+				table.String("title")
+			})
+		},
+	}
+}
+
+func TestTransactionalMigrationRollsBack(t *testing.T) {
+	migrator, _ := newChecksumMigrator(t)
+
+	migration := failingPostsMigration(1)
+	migrator.registry.Register(&migration)
+
+	err := migrator.Up()
+	if err == nil {
+		t.Fatal("Expected migration to fail")
+	}
+	if strings.Contains(err.Error(), "not rolled back") {
+		t.Errorf("Transactional failure should not report applied statements, got: %v", err)
+	}
+	if migrator.schema.HasTable("posts") {
+		t.Error("Expected the transaction to roll back the posts table")
+	}
+}
+
+func TestNonTransactionalMigrationReportsAppliedStatements(t *testing.T) {
+	migrator, db := newChecksumMigrator(t)
+
+	migrator.registry.Register(&concurrentIndexMigration{checksumMigration: failingPostsMigration(1)})
+
+	err := migrator.Up()
+	if err == nil {
+		t.Fatal("Expected migration to fail")
+	}
+	if !strings.Contains(err.Error(), "1 statement(s) were applied and not rolled back") ||
+		!strings.Contains(err.Error(), "CREATE TABLE posts") {
+		t.Errorf("Expected applied statements in the error, got: %v", err)
+	}
+	if !migrator.schema.HasTable("posts") {
+		t.Error("Expected the posts table to remain without a transaction")
+	}
+
+	var count int64
+	db.Table("migrations").Count(&count)
+	if count != 0 {
+		t.Errorf("Failed migration must not be recorded, found %d records", count)
+	}
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galaplate/core/config"
+	"github.com/galaplate/core/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultConnectRetries    = 3
+	defaultConnectRetryDelay = time.Second
+	maxConnectRetryDelay     = 30 * time.Second
+)
+
+// configError marks connection failures caused by the configuration, which
+// retrying won't fix
+type configError struct {
+	error
+}
+
+func (e configError) Unwrap() error {
+	return e.error
+}
+
+// Open connects to the default connection and makes it the Connect connection.
+// Failed attempts are retried with a growing delay, so the application can start
+// while the database container is still booting
+//
+//	db, err := database.Open(database.DefaultGormConfig())
+func Open(cfg *Config) (*gorm.DB, error) {
+	if cfg == nil {
+		cfg = DefaultGormConfig()
+	}
+
+	dbConn := config.ConfigString("database.default")
+
+	db, err := openWithRetry(dbConn, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	connectionsMu.Lock()
+	connectionConfig = cfg
+	connections[dbConn] = db
+	connectionsMu.Unlock()
+
+	Connect = db
+
+	return db, nil
+}
+
+// openWithRetry opens a connection, retrying up to cfg.Retries times
+func openWithRetry(dbConn string, cfg *Config) (*gorm.DB, error) {
+	delay := cfg.RetryDelay
+	if delay <= 0 {
+		delay = defaultConnectRetryDelay
+	}
+
+	for attempt := 0; ; attempt++ {
+		db, err := openConnection(dbConn, cfg)
+		if err == nil {
+			return db, nil
+		}
+
+		var invalid configError
+		if errors.As(err, &invalid) || attempt >= cfg.Retries {
+			return nil, err
+		}
+
+		logger.Warn("database@Open", map[string]any{
+			"connection": dbConn,
+			"attempt":    attempt + 1,
+			"retry_in":   delay.String(),
+			"error":      err.Error(),
+		})
+
+		time.Sleep(delay)
+		delay = min(delay*2, maxConnectRetryDelay)
+	}
+}
+
+// Ping checks the named connections, or the default connection when none is
+// given, and returns the first failure
+//
+//	if err := database.Ping(ctx); err != nil {
+//		return c.Status(fiber.StatusServiceUnavailable).SendString(err.Error())
+//	}
+func Ping(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		names = []string{config.ConfigString("database.default")}
+	}
+
+	for _, name := range names {
+		db, err := connectionForPing(name)
+		if err != nil {
+			return err
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			return fmt.Errorf("database connection %q: %w", name, err)
+		}
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return fmt.Errorf("database connection %q is unreachable: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// connectionForPing returns an opened connection without panicking
+func connectionForPing(name string) (*gorm.DB, error) {
+	if name == config.ConfigString("database.default") && Connect != nil {
+		return Connect, nil
+	}
+
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+
+	if db, ok := connections[name]; ok {
+		return db, nil
+	}
+
+	return nil, fmt.Errorf("database connection %q is not open", name)
+}
+
+// connectRetries reads database.connect_retries, defaulting to three retries
+func connectRetries() int {
+	if !config.GetGlobal().Has("database.connect_retries") {
+		return defaultConnectRetries
+	}
+	return config.ConfigInt("database.connect_retries")
+}
+
+// connectRetryDelay reads database.connect_retry_delay ("500ms" or seconds)
+func connectRetryDelay() time.Duration {
+	return parseDuration(config.ConfigString("database.connect_retry_delay"))
+}
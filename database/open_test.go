@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/galaplate/core/config"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestOpenDoesNotRetryInvalidConfig(t *testing.T) {
+	config.GetGlobal().Set("database.connections.test_invalid.driver", "oracle")
+
+	start := time.Now()
+	_, err := openWithRetry("test_invalid", &Config{GormConfig: &gorm.Config{}, Retries: 3, RetryDelay: time.Second})
+	if err == nil {
+		t.Fatal("Expected an unsupported driver error")
+	}
+	var invalid configError
+	if !errors.As(err, &invalid) {
+		t.Errorf("Expected a configuration error, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Expected configuration errors not to be retried")
+	}
+}
+
+func TestOpenRetriesUnreachableDatabase(t *testing.T) {
+	config.GetGlobal().Set("database.connections.test_unreachable.driver", "mysql")
+	config.GetGlobal().Set("database.connections.test_unreachable.host", "127.0.0.1")
+	config.GetGlobal().Set("database.connections.test_unreachable.port", "1")
+
+	start := time.Now()
+	_, err := openWithRetry("test_unreachable", &Config{GormConfig: &gorm.Config{}, Retries: 2, RetryDelay: 50 * time.Millisecond})
+	if err == nil {
+		t.Fatal("Expected the connection to fail")
+	}
+	// Two retries wait 50ms then 100ms
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected the connection to be retried with backoff, gave up after %s", elapsed)
+	}
+}
+
+func TestPing(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "ping.sqlite")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	SetConnection("test_ping", db)
+
+	if err := Ping(context.Background(), "test_ping"); err != nil {
+		t.Errorf("Expected ping to succeed, got %v", err)
+	}
+	if err := Ping(context.Background(), "test_not_opened"); err == nil {
+		t.Error("Expected ping of a connection that isn't open to fail")
+	}
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// stickyReadsKey is the context key holding the sticky reads state
+type stickyReadsKey struct{}
+
+// stickyReads records whether a write already happened within a context
+type stickyReads struct {
+	wrote atomic.Bool
+}
+
+// WithStickyReads returns a context in which reads following a write go to the
+// primary instead of a replica, so they see that write despite replication lag
+//
+//	ctx := database.WithStickyReads(context.Background())
+//	db := database.Connect.WithContext(ctx)
+func WithStickyReads(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(stickyReadsKey{}).(*stickyReads); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, stickyReadsKey{}, &stickyReads{})
+}
+
+// StickyReads is a Fiber middleware making reads stick to the primary after a write
+// in the same request. Queries must use the request context:
+//
+//	database.Connect.WithContext(c.UserContext()).Find(&users)
+func StickyReads() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(WithStickyReads(c.UserContext()))
+		return c.Next()
+	}
+}
+
+// useResolver routes writes to the primaries and reads to the replicas. Transactions
+// already stay on a single connection, the sticky reads callbacks handle the rest
+func useResolver(db *gorm.DB, sources, replicas []gorm.Dialector) error {
+	// Registered ahead of the resolver, whose callbacks also run before everything else
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().After("gorm:create").Register("galaplate:sticky_writes", markWrite),
+		callbacks.Update().After("gorm:update").Register("galaplate:sticky_writes", markWrite),
+		callbacks.Delete().After("gorm:delete").Register("galaplate:sticky_writes", markWrite),
+		callbacks.Raw().After("gorm:raw").Register("galaplate:sticky_writes", markRawWrite),
+		callbacks.Query().Before("*").Register("galaplate:sticky_reads", stickToPrimary),
+		callbacks.Row().Before("*").Register("galaplate:sticky_reads", stickToPrimary),
+		callbacks.Raw().Before("*").Register("galaplate:sticky_reads", stickToPrimary),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.Use(dbresolver.Register(dbresolver.Config{
+		Sources:  sources,
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}))
+}
+
+// stickyState returns the sticky reads state of a statement, if any
+func stickyState(db *gorm.DB) *stickyReads {
+	if db.Statement.Context == nil {
+		return nil
+	}
+	state, _ := db.Statement.Context.Value(stickyReadsKey{}).(*stickyReads)
+	return state
+}
+
+// markWrite records a write in the statement context
+func markWrite(db *gorm.DB) {
+	if state := stickyState(db); state != nil && db.Error == nil {
+		state.wrote.Store(true)
+	}
+}
+
+// markRawWrite records a write for raw statements other than SELECT
+func markRawWrite(db *gorm.DB) {
+	sql := strings.TrimSpace(db.Statement.SQL.String())
+	if len(sql) >= 6 && strings.EqualFold(sql[:6], "select") {
+		return
+	}
+	markWrite(db)
+}
+
+// stickToPrimary sends a read to the primary once the context saw a write
+func stickToPrimary(db *gorm.DB) {
+	if state := stickyState(db); state != nil && state.wrote.Load() {
+		dbresolver.Write.ModifyStatement(db.Statement)
+	}
+}
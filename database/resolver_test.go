@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/galaplate/core/config"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newReplicatedConnection opens a connection with one write and one read SQLite
+// database, each holding a users table with a marker row telling them apart
+func newReplicatedConnection(t *testing.T, name string) *gorm.DB {
+	dir := t.TempDir()
+	primaryPath := filepath.Join(dir, "primary.sqlite")
+	replicaPath := filepath.Join(dir, "replica.sqlite")
+
+	for path, marker := range map[string]string{primaryPath: "primary", replicaPath: "replica"} {
+		db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", path, err)
+		}
+		db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)")
+		db.Exec("INSERT INTO users (name) VALUES (?)", marker)
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}
+
+	config.GetGlobal().Set("database.connections."+name+".driver", "sqlite")
+	config.GetGlobal().Set("database.connections."+name+".write", map[string]any{"database": primaryPath})
+	config.GetGlobal().Set("database.connections."+name+".read", map[string]any{"database": []any{replicaPath}})
+
+	return Connection(name)
+}
+
+func TestReadWriteSplitting(t *testing.T) {
+	db := newReplicatedConnection(t, "test_replicated")
+
+	var marker string
+	db.Raw("SELECT name FROM users WHERE id = 1").Scan(&marker)
+	if marker != "replica" {
+		t.Errorf("Expected reads to go to the replica, got %s", marker)
+	}
+
+	if err := db.Exec("INSERT INTO users (name) VALUES ('alice')").Error; err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+
+	var count int64
+	db.Table("users").Count(&count)
+	if count != 1 {
+		t.Errorf("Expected the write to reach the primary only, replica has %d rows", count)
+	}
+
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.Table("users").Count(&count)
+		return nil
+	})
+	if count != 2 {
+		t.Errorf("Expected reads in a transaction to use the primary, got %d rows", count)
+	}
+}
+
+func TestStickyReads(t *testing.T) {
+	db := newReplicatedConnection(t, "test_sticky")
+
+	ctx := WithStickyReads(context.Background())
+	session := db.WithContext(ctx)
+
+	var marker string
+	session.Raw("SELECT name FROM users WHERE id = 1").Scan(&marker)
+	if marker != "replica" {
+		t.Errorf("Expected reads before a write to go to the replica, got %s", marker)
+	}
+
+	if err := session.Table("users").Create(map[string]any{"name": "alice"}).Error; err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+
+	var count int64
+	session.Table("users").Count(&count)
+	if count != 2 {
+		t.Errorf("Expected reads after a write to stick to the primary, got %d rows", count)
+	}
+
+	db.Table("users").Count(&count)
+	if count != 1 {
+		t.Errorf("Expected other contexts to keep reading the replica, got %d rows", count)
+	}
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -15,6 +16,20 @@ import (
 type Schema struct {
 	db       *gorm.DB
 	dbDriver string
+
+	// inTransaction is set by the migrator when db is a migration transaction.
+	// Statements that can't run inside a transaction are then queued in deferred
+	inTransaction bool
+	deferred      []string
+
+	// pretend records statements in pretended instead of executing them.
+	// Used by the migrator to checksum a migration's SQL
+	pretend   bool
+	pretended []string
+
+	// applied lists the statements executed successfully, so a failed
+	// migration can report how far it got on drivers without transactional DDL
+	applied []string
 }
 
 // NewSchema creates a new Schema instance
@@ -25,13 +40,37 @@ func NewSchema() *Schema {
 	}
 }
 
+// NewSchemaOn creates a Schema on an already opened connection, such as the one
+// passed to a seeder
+func NewSchemaOn(db *gorm.DB) *Schema {
+	return &Schema{
+		db:       db,
+		dbDriver: supports.MapPostgres(db.Dialector.Name()),
+	}
+}
+
+// NewConnectionSchema creates a Schema on a named connection, opening it with
+// OpenConnection
+//
+//	analytics, err := database.NewConnectionSchema("analytics")
+func NewConnectionSchema(name string) (*Schema, error) {
+	db, err := OpenConnection(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{
+		db:       db,
+		dbDriver: supports.MapPostgres(GetDriver(name)),
+	}, nil
+}
+
 // Create creates a new table
 func (s *Schema) Create(tableName string, callback func(table *Blueprint)) error {
 	blueprint := NewBlueprint(tableName, s.dbDriver)
 	callback(blueprint)
 
-	sql := blueprint.ToSQL()
-	return s.db.Exec(sql).Error
+	return s.build(blueprint)
 }
 
 // Table modifies an existing table
@@ -40,20 +79,74 @@ func (s *Schema) Table(tableName string, callback func(table *Blueprint)) error
 	blueprint.SetMode("alter")
 	callback(blueprint)
 
-	sql := blueprint.ToSQL()
-	return s.db.Exec(sql).Error
+	return s.build(blueprint)
+}
+
+// build executes the blueprint SQL, running CONCURRENTLY indexes on their own
+func (s *Schema) build(blueprint *Blueprint) error {
+	concurrent := blueprint.takeConcurrentIndexes()
+
+	statements := blueprint.ToStatements()
+	if err := blueprint.Err(); err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		if err := s.exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return s.execOutsideTransaction(concurrent)
+}
+
+// exec runs a statement, or records it when the schema is pretending
+func (s *Schema) exec(sql string) error {
+	if s.pretend {
+		s.pretended = append(s.pretended, sql)
+		return nil
+	}
+	if err := s.db.Exec(sql).Error; err != nil {
+		return err
+	}
+	s.applied = append(s.applied, sql)
+	return nil
+}
+
+// Applied returns the statements executed successfully by this schema
+func (s *Schema) Applied() []string {
+	return s.applied
+}
+
+// execOutsideTransaction runs statements that can't be part of a transaction block.
+// Inside a migration they are queued and executed by the migrator after commit
+func (s *Schema) execOutsideTransaction(statements []string) error {
+	if s.inTransaction && !s.pretend {
+		s.deferred = append(s.deferred, statements...)
+		return nil
+	}
+
+	for _, statement := range statements {
+		if err := s.exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Drop drops a table
 func (s *Schema) Drop(tableName string) error {
 	sql := fmt.Sprintf("DROP TABLE %s;", tableName)
-	return s.db.Exec(sql).Error
+	return s.exec(sql)
 }
 
 // DropIfExists drops a table if it exists
 func (s *Schema) DropIfExists(tableName string) error {
 	sql := fmt.Sprintf("DROP TABLE IF EXISTS %s;", tableName)
-	return s.db.Exec(sql).Error
+	return s.exec(sql)
 }
 
 // HasTable checks if table exists
@@ -99,6 +192,9 @@ type Blueprint struct {
 	dropColumns  []string
 	dropIndexes  []string
 	dropForeigns []string
+	checks       []CheckConstraint
+	dropChecks   []string
+	errors       []error
 }
 
 // NewBlueprint creates a new Blueprint
@@ -120,6 +216,8 @@ func NewBlueprint(tableName, dbDriver string) *Blueprint {
 		dropColumns:  []string{},
 		dropIndexes:  []string{},
 		dropForeigns: []string{},
+		checks:       []CheckConstraint{},
+		dropChecks:   []string{},
 	}
 }
 
@@ -128,6 +226,21 @@ func (b *Blueprint) SetMode(mode string) {
 	b.mode = mode
 }
 
+// Err returns the errors collected while building the blueprint, if any
+func (b *Blueprint) Err() error {
+	return errors.Join(b.errors...)
+}
+
+// addError records an error, deduplicating repeated messages from repeated ToSQL calls
+func (b *Blueprint) addError(err error) {
+	for _, existing := range b.errors {
+		if existing.Error() == err.Error() {
+			return
+		}
+	}
+	b.errors = append(b.errors, err)
+}
+
 // addOrUpdateColumn adds a column or updates the last one if it's a modify with matching name
 func (b *Blueprint) addOrUpdateColumn(col Column) {
 	// If last column is a modify with matching name, update it instead of appending
@@ -198,15 +311,31 @@ type Column struct {
 	Unsigned   bool
 	Comment    string
 	After      string // For MySQL ALTER TABLE
+	First      bool   // For MySQL ALTER TABLE
 	EnumValues []string
 	Modify     bool // Whether this column is being modified
+
+	GeneratedAs     string // Expression for a generated column
+	GeneratedStored bool   // STORED when true, VIRTUAL otherwise
+	OnUpdateCurrent bool   // ON UPDATE CURRENT_TIMESTAMP (MySQL only)
+	ElementType     string // Element type for array columns
+	Identity        string // "by default" or "always" for identity columns
 }
 
 // Index represents a database index
 type Index struct {
-	Name    string
-	Columns []string
-	Type    string // "index", "unique", "primary"
+	Name         string
+	Columns      []string
+	Type         string // "index", "unique", "primary"
+	Method       string // Index method, e.g. "btree", "hash", "gin", "gist"
+	Where        string // Condition for partial indexes
+	Concurrently bool   // CREATE INDEX CONCURRENTLY (PostgreSQL only)
+}
+
+// CheckConstraint represents a table-level CHECK constraint
+type CheckConstraint struct {
+	Name       string
+	Expression string
 }
 
 // ForeignKey represents a foreign key constraint
@@ -572,6 +701,121 @@ func (b *Blueprint) LongText(name string) *Blueprint {
 	return b
 }
 
+// JSONB creates a JSONB column (JSON on MySQL, TEXT on SQLite)
+func (b *Blueprint) JSONB(name string) *Blueprint {
+	col := Column{
+		Name:     name,
+		Type:     "jsonb",
+		Nullable: true,
+	}
+	b.addOrUpdateColumn(col)
+	return b
+}
+
+// Array creates a PostgreSQL array column of the given element type, e.g.
+// Array("tags", "text") -> TEXT[]. MySQL falls back to JSON and SQLite to TEXT
+func (b *Blueprint) Array(name, elementType string, length ...int) *Blueprint {
+	col := Column{
+		Name:        name,
+		Type:        "array",
+		ElementType: elementType,
+		Nullable:    true,
+	}
+	if len(length) > 0 {
+		col.Length = length[0]
+	}
+	b.addOrUpdateColumn(col)
+	return b
+}
+
+// Inet creates an INET column (alias for IPAddress)
+func (b *Blueprint) Inet(name string) *Blueprint {
+	return b.IPAddress(name)
+}
+
+// TsVector creates a TSVECTOR column for full text search (PostgreSQL only)
+func (b *Blueprint) TsVector(name string) *Blueprint {
+	col := Column{
+		Name:     name,
+		Type:     "tsvector",
+		Nullable: true,
+	}
+	b.addOrUpdateColumn(col)
+	return b
+}
+
+// Column group helpers
+
+// SoftDeletes creates a nullable deleted_at TIMESTAMP column with an index
+func (b *Blueprint) SoftDeletes(column ...string) *Blueprint {
+	name := "deleted_at"
+	if len(column) > 0 {
+		name = column[0]
+	}
+	b.Timestamp(name).Nullable()
+	b.Index([]string{name})
+	return b
+}
+
+// RememberToken creates a nullable remember_token VARCHAR(100) column
+func (b *Blueprint) RememberToken() *Blueprint {
+	return b.String("remember_token", 100).Nullable()
+}
+
+// IPAddress creates a column for IPv4/IPv6 addresses (INET on PostgreSQL)
+func (b *Blueprint) IPAddress(column ...string) *Blueprint {
+	name := "ip_address"
+	if len(column) > 0 {
+		name = column[0]
+	}
+	col := Column{
+		Name:     name,
+		Type:     "ipaddress",
+		Nullable: true,
+	}
+	b.addOrUpdateColumn(col)
+	return b
+}
+
+// MacAddress creates a column for MAC addresses (MACADDR on PostgreSQL)
+func (b *Blueprint) MacAddress(column ...string) *Blueprint {
+	name := "mac_address"
+	if len(column) > 0 {
+		name = column[0]
+	}
+	col := Column{
+		Name:     name,
+		Type:     "macaddress",
+		Nullable: true,
+	}
+	b.addOrUpdateColumn(col)
+	return b
+}
+
+// ULID creates a CHAR(26) column for ULIDs
+func (b *Blueprint) ULID(column ...string) *Blueprint {
+	name := "ulid"
+	if len(column) > 0 {
+		name = column[0]
+	}
+	col := Column{
+		Name:     name,
+		Type:     "ulid",
+		Nullable: true,
+	}
+	b.addOrUpdateColumn(col)
+	return b
+}
+
+// Morphs creates {name}_type and {name}_id columns for a polymorphic relation
+// together with a composite index on both columns
+func (b *Blueprint) Morphs(name string) *Blueprint {
+	b.String(name + "_type").NotNullable()
+	b.BigInteger(name + "_id").NotNullable().Unsigned()
+	b.Index([]string{name + "_type", name + "_id"})
+	return b
+}
+
 // Column modifier methods - chainable
 
 // NotNullable makes the column NOT NULL
@@ -630,6 +874,94 @@ func (b *Blueprint) Signed() *Blueprint {
 	return b
 }
 
+// StoredAs makes the column a STORED generated column computed from expression
+func (b *Blueprint) StoredAs(expression string) *Blueprint {
+	if len(b.columns) > 0 {
+		b.columns[len(b.columns)-1].GeneratedAs = expression
+		b.columns[len(b.columns)-1].GeneratedStored = true
+	}
+	return b
+}
+
+// VirtualAs makes the column a VIRTUAL generated column computed from expression
+// (not supported on PostgreSQL)
+func (b *Blueprint) VirtualAs(expression string) *Blueprint {
+	if len(b.columns) > 0 {
+		b.columns[len(b.columns)-1].GeneratedAs = expression
+		b.columns[len(b.columns)-1].GeneratedStored = false
+	}
+	return b
+}
+
+// After places the column after another column when altering a MySQL table.
+// Other drivers always append columns and record an error
+func (b *Blueprint) After(column string) *Blueprint {
+	if b.dbDriver != "mysql" {
+		b.addError(fmt.Errorf("%s does not support column placement (AFTER %s); columns are always appended", b.dbDriver, column))
+		return b
+	}
+	if len(b.columns) > 0 {
+		b.columns[len(b.columns)-1].After = column
+		b.columns[len(b.columns)-1].First = false
+	}
+	return b
+}
+
+// First places the column first when altering a MySQL table.
+// Other drivers always append columns and record an error
+func (b *Blueprint) First() *Blueprint {
+	if b.dbDriver != "mysql" {
+		b.addError(fmt.Errorf("%s does not support column placement (FIRST); columns are always appended", b.dbDriver))
+		return b
+	}
+	if len(b.columns) > 0 {
+		b.columns[len(b.columns)-1].First = true
+		b.columns[len(b.columns)-1].After = ""
+	}
+	return b
+}
+
+// UseCurrent sets the column default to CURRENT_TIMESTAMP
+func (b *Blueprint) UseCurrent() *Blueprint {
+	return b.Default("CURRENT_TIMESTAMP")
+}
+
+// UseCurrentOnUpdate sets the column to CURRENT_TIMESTAMP whenever the row is
+// updated (MySQL only)
+func (b *Blueprint) UseCurrentOnUpdate() *Blueprint {
+	if len(b.columns) > 0 {
+		b.columns[len(b.columns)-1].OnUpdateCurrent = true
+	}
+	return b
+}
+
+// Identity turns an integer column into a GENERATED BY DEFAULT AS IDENTITY
+// column on PostgreSQL instead of SERIAL. MySQL falls back to AUTO_INCREMENT
+func (b *Blueprint) Identity() *Blueprint {
+	if len(b.columns) > 0 {
+		b.columns[len(b.columns)-1].Identity = "by default"
+		b.columns[len(b.columns)-1].Auto = true
+		b.columns[len(b.columns)-1].Nullable = false
+	}
+	return b
+}
+
+// IdentityAlways turns an integer column into a GENERATED ALWAYS AS IDENTITY
+// column on PostgreSQL. MySQL falls back to AUTO_INCREMENT
+func (b *Blueprint) IdentityAlways() *Blueprint {
+	b.Identity()
+	if len(b.columns) > 0 {
+		b.columns[len(b.columns)-1].Identity = "always"
+	}
+	return b
+}
+
+// Check adds a named table-level CHECK constraint
+func (b *Blueprint) Check(name, expression string) *Blueprint {
+	b.checks = append(b.checks, CheckConstraint{Name: name, Expression: expression})
+	return b
+}
+
 // Index methods
 
 // Index creates an index
@@ -680,6 +1012,35 @@ func (b *Blueprint) Primary(columns []string, name ...string) *Blueprint {
 	return b
 }
 
+// Index modifier methods - chainable, apply to the last index
+
+// Using sets the index method, e.g. "gin" or "gist" on PostgreSQL,
+// "btree" or "hash" on MySQL
+func (b *Blueprint) Using(method string) *Blueprint {
+	if len(b.indexes) > 0 {
+		b.indexes[len(b.indexes)-1].Method = strings.ToLower(method)
+	}
+	return b
+}
+
+// Where makes the last index a partial index (PostgreSQL and SQLite)
+func (b *Blueprint) Where(condition string) *Blueprint {
+	if len(b.indexes) > 0 {
+		b.indexes[len(b.indexes)-1].Where = condition
+	}
+	return b
+}
+
+// Concurrently builds the last index with CREATE INDEX CONCURRENTLY on PostgreSQL.
+// The statement runs outside the migration transaction; other drivers create
+// the index normally
+func (b *Blueprint) Concurrently() *Blueprint {
+	if len(b.indexes) > 0 {
+		b.indexes[len(b.indexes)-1].Concurrently = true
+	}
+	return b
+}
+
 // Foreign creates a foreign key
 func (b *Blueprint) Foreign(column string) *ForeignKeyBuilder {
 	return &ForeignKeyBuilder{
@@ -693,36 +1054,38 @@ type ForeignKeyBuilder struct {
 	blueprint *Blueprint
 	column    string
 	fk        ForeignKey
+	index     int
+	attached  bool
 }
 
 // References sets the referenced column
 func (fkb *ForeignKeyBuilder) References(column string) *ForeignKeyBuilder {
 	fkb.fk.References = column
-	return fkb
+	return fkb.sync()
 }
 
 // On sets the referenced table
 func (fkb *ForeignKeyBuilder) On(table string) *ForeignKeyBuilder {
 	fkb.fk.On = table
-	return fkb
+	return fkb.sync()
 }
 
 // OnDelete sets the ON DELETE action
 func (fkb *ForeignKeyBuilder) OnDelete(action string) *ForeignKeyBuilder {
 	fkb.fk.OnDelete = action
-	return fkb
+	return fkb.sync()
 }
 
 // OnUpdate sets the ON UPDATE action
 func (fkb *ForeignKeyBuilder) OnUpdate(action string) *ForeignKeyBuilder {
 	fkb.fk.OnUpdate = action
-	return fkb
+	return fkb.sync()
 }
 
 // Deferrable marks the foreign key as deferrable
 func (fkb *ForeignKeyBuilder) Deferrable() *ForeignKeyBuilder {
 	fkb.fk.Deferrable = true
-	return fkb
+	return fkb.sync()
 }
 
 // InitiallyDeferred marks the foreign key as initially deferred
@@ -730,16 +1093,119 @@ func (fkb *ForeignKeyBuilder) Deferrable() *ForeignKeyBuilder {
 func (fkb *ForeignKeyBuilder) InitiallyDeferred() *ForeignKeyBuilder {
 	fkb.fk.Deferrable = true
 	fkb.fk.InitiallyDeferred = true
-	return fkb
+	return fkb.sync()
+}
+
+// CascadeOnDelete sets ON DELETE CASCADE
+func (fkb *ForeignKeyBuilder) CascadeOnDelete() *ForeignKeyBuilder {
+	return fkb.OnDelete("CASCADE")
+}
+
+// NullOnDelete sets ON DELETE SET NULL
+func (fkb *ForeignKeyBuilder) NullOnDelete() *ForeignKeyBuilder {
+	return fkb.OnDelete("SET NULL")
+}
+
+// RestrictOnDelete sets ON DELETE RESTRICT
+func (fkb *ForeignKeyBuilder) RestrictOnDelete() *ForeignKeyBuilder {
+	return fkb.OnDelete("RESTRICT")
+}
+
+// CascadeOnUpdate sets ON UPDATE CASCADE
+func (fkb *ForeignKeyBuilder) CascadeOnUpdate() *ForeignKeyBuilder {
+	return fkb.OnUpdate("CASCADE")
 }
 
 // Finish completes the foreign key definition
 func (fkb *ForeignKeyBuilder) Finish() *Blueprint {
 	fkb.fk.Column = fkb.column
+	if fkb.attached {
+		fkb.blueprint.foreign[fkb.index] = fkb.fk
+		return fkb.blueprint
+	}
 	fkb.blueprint.foreign = append(fkb.blueprint.foreign, fkb.fk)
 	return fkb.blueprint
 }
 
+// sync writes the builder state back to the blueprint once the foreign key
+// has been attached by Constrained, so chained calls need no Finish()
+func (fkb *ForeignKeyBuilder) sync() *ForeignKeyBuilder {
+	if fkb.attached {
+		fkb.fk.Column = fkb.column
+		fkb.blueprint.foreign[fkb.index] = fkb.fk
+	}
+	return fkb
+}
+
+// ForeignID creates an unsigned BIGINT column meant to reference another table's id
+func (b *Blueprint) ForeignID(name string) *ForeignIDBuilder {
+	b.BigInteger(name).Unsigned()
+	return &ForeignIDBuilder{
+		blueprint: b,
+		column:    name,
+	}
+}
+
+// ForeignIDBuilder configures a column created by ForeignID
+type ForeignIDBuilder struct {
+	blueprint *Blueprint
+	column    string
+}
+
+// Nullable makes the foreign id column nullable
+func (fib *ForeignIDBuilder) Nullable() *ForeignIDBuilder {
+	fib.blueprint.Nullable()
+	return fib
+}
+
+// NotNullable makes the foreign id column NOT NULL
+func (fib *ForeignIDBuilder) NotNullable() *ForeignIDBuilder {
+	fib.blueprint.NotNullable()
+	return fib
+}
+
+// Unique makes the foreign id column unique, e.g. for one-to-one relationships
+func (fib *ForeignIDBuilder) Unique() *ForeignIDBuilder {
+	fib.blueprint.Unique()
+	return fib
+}
+
+// Default sets the default value of the foreign id column
+func (fib *ForeignIDBuilder) Default(value interface{}) *ForeignIDBuilder {
+	fib.blueprint.Default(value)
+	return fib
+}
+
+// Constrained adds the foreign key constraint. The referenced table defaults to
+// the plural of the column without its "_id" suffix (user_id -> users) and the
+// referenced column defaults to "id"
+func (fib *ForeignIDBuilder) Constrained(tableAndColumn ...string) *ForeignKeyBuilder {
+	table := supports.Pluralize(strings.TrimSuffix(fib.column, "_id"))
+	column := "id"
+	if len(tableAndColumn) > 0 && tableAndColumn[0] != "" {
+		table = tableAndColumn[0]
+	}
+	if len(tableAndColumn) > 1 && tableAndColumn[1] != "" {
+		column = tableAndColumn[1]
+	}
+
+	b := fib.blueprint
+	b.foreign = append(b.foreign, ForeignKey{Column: fib.column, References: column, On: table})
+
+	return &ForeignKeyBuilder{
+		blueprint: b,
+		column:    fib.column,
+		fk:        b.foreign[len(b.foreign)-1],
+		index:     len(b.foreign) - 1,
+		attached:  true,
+	}
+}
+
+// Blueprint returns the underlying blueprint to continue chaining columns
+func (fib *ForeignIDBuilder) Blueprint() *Blueprint {
+	return fib.blueprint
+}
+
 // Modify methods
 
 // Modify modifies an existing column - returns a special blueprint for chaining
@@ -778,18 +1244,46 @@ func (b *Blueprint) DropForeign(constraintName string) *Blueprint {
 	return b
 }
 
+// DropCheck drops a CHECK constraint
+func (b *Blueprint) DropCheck(constraintName string) *Blueprint {
+	b.dropChecks = append(b.dropChecks, constraintName)
+	return b
+}
+
+// DropSoftDeletes drops the deleted_at column and its index created by SoftDeletes
+func (b *Blueprint) DropSoftDeletes(column ...string) *Blueprint {
+	name := "deleted_at"
+	if len(column) > 0 {
+		name = column[0]
+	}
+	b.DropIndex(fmt.Sprintf("idx_%s_%s", b.tableName, name))
+	return b.DropColumn(name)
+}
+
+// DropMorphs drops the columns and index created by Morphs
+func (b *Blueprint) DropMorphs(name string) *Blueprint {
+	b.DropIndex(fmt.Sprintf("idx_%s_%s_type_%s_id", b.tableName, name, name))
+	b.DropColumn(name + "_type")
+	return b.DropColumn(name + "_id")
+}
+
 // ToSQL converts the blueprint to SQL - handles database differences
 func (b *Blueprint) ToSQL() string {
+	return strings.Join(b.ToStatements(), "\n")
+}
+
+// ToStatements generates the blueprint SQL as individual statements
+func (b *Blueprint) ToStatements() []string {
 	switch b.mode {
 	case "alter":
-		return b.toAlterSQL()
+		return b.alterStatements()
 	default:
-		return b.toCreateSQL()
+		return b.createStatements()
 	}
 }
 
-// toCreateSQL generates CREATE TABLE SQL
-func (b *Blueprint) toCreateSQL() string {
+// createStatements generates CREATE TABLE SQL followed by its CREATE INDEX statements
+func (b *Blueprint) createStatements() []string {
 	var sql strings.Builder
 
 	sql.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", b.tableName))
@@ -802,9 +1296,11 @@ func (b *Blueprint) toCreateSQL() string {
 
 	sql.WriteString("  " + strings.Join(columnSQLs, ",\n  "))
 
-	// Add indexes - primary keys are always inline, regular indexes are separate for PostgreSQL
+	// Add indexes - primary keys are always inline, regular indexes are separate
+	// for PostgreSQL and SQLite which don't support inline INDEX definitions.
+	// SQLite rejects the inline form, so no ran migration had that SQL
 	for _, idx := range b.indexes {
-		if idx.Type == "primary" || b.dbDriver != "postgres" {
+		if idx.Type == "primary" || b.dbDriver == "mysql" {
 			sql.WriteString(",\n  " + b.indexToSQL(idx))
 		}
 	}
@@ -814,31 +1310,29 @@ func (b *Blueprint) toCreateSQL() string {
 		sql.WriteString(",\n  " + b.foreignKeyToSQL(fk))
 	}
 
+	// Add check constraints
+	for _, check := range b.checks {
+		sql.WriteString(",\n  " + b.checkToSQL(check))
+	}
+
 	sql.WriteString("\n);")
 
-	// For PostgreSQL, add separate CREATE INDEX statements for non-primary indexes
-	if b.dbDriver == "postgres" {
+	statements := []string{sql.String()}
+
+	// For PostgreSQL and SQLite, add separate CREATE INDEX statements for non-primary indexes
+	if b.dbDriver != "mysql" {
 		for _, idx := range b.indexes {
 			if idx.Type != "primary" {
-				var indexSQL string
-				switch idx.Type {
-				case "unique":
-					indexSQL = fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s);",
-						idx.Name, b.tableName, strings.Join(idx.Columns, ", "))
-				default:
-					indexSQL = fmt.Sprintf("CREATE INDEX %s ON %s (%s);",
-						idx.Name, b.tableName, strings.Join(idx.Columns, ", "))
-				}
-				sql.WriteString("\n" + indexSQL)
+				statements = append(statements, b.createIndexSQL(idx))
 			}
 		}
 	}
 
-	return sql.String()
+	return statements
 }
 
-// toAlterSQL generates ALTER TABLE SQL
-func (b *Blueprint) toAlterSQL() string {
+// alterStatements generates ALTER TABLE SQL
+func (b *Blueprint) alterStatements() []string {
 	var sqls []string
 
 	// Drop foreign keys
@@ -856,6 +1350,18 @@ func (b *Blueprint) toAlterSQL() string {
 		sqls = append(sqls, sql)
 	}
 
+	// Drop check constraints
+	for _, constraint := range b.dropChecks {
+		switch b.dbDriver {
+		case "mysql":
+			sqls = append(sqls, fmt.Sprintf("ALTER TABLE %s DROP CHECK %s;", b.tableName, b.quoteIdentifier(constraint)))
+		case "postgres":
+			sqls = append(sqls, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", b.tableName, b.quoteIdentifier(constraint)))
+		case "sqlite":
+			b.addError(fmt.Errorf("sqlite cannot drop check constraint %s from an existing table", constraint))
+		}
+	}
+
 	// Drop indexes
 	for _, indexName := range b.dropIndexes {
 		var sql string
@@ -876,45 +1382,47 @@ func (b *Blueprint) toAlterSQL() string {
 
 	// Add or modify columns
 	for _, col := range b.columns {
-		var sql string
 		if col.Modify {
-			sql = b.getModifyColumnSQL(col)
+			sqls = append(sqls, b.modifyColumnStatements(col)...)
 		} else {
-			sql = fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", b.tableName, b.columnToSQL(col))
+			sqls = append(sqls, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", b.tableName, b.columnToSQL(col)))
 		}
-		sqls = append(sqls, sql)
 	}
 
 	// Add indexes
 	for _, idx := range b.indexes {
-		// Quote column names
-		quotedColumns := make([]string, len(idx.Columns))
-		for i, col := range idx.Columns {
-			quotedColumns[i] = b.quoteIdentifier(col)
+		sqls = append(sqls, b.createIndexSQL(idx))
+	}
+
+	// Add foreign keys, named <table>_<column>_foreign so DropForeign can remove them
+	for _, fk := range b.foreign {
+		if b.dbDriver == "sqlite" {
+			b.addError(fmt.Errorf("sqlite cannot add foreign key on %s to an existing table", fk.Column))
+			continue
 		}
+		name := fmt.Sprintf("%s_%s_foreign", b.tableName, fk.Column)
+		sqls = append(sqls, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;", b.tableName, b.quoteIdentifier(name), b.foreignKeyToSQL(fk)))
+	}
 
-		var sql string
-		switch idx.Type {
-		case "unique":
-			sql = fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s);",
-				b.quoteIdentifier(idx.Name), b.tableName, strings.Join(quotedColumns, ", "))
-		default:
-			sql = fmt.Sprintf("CREATE INDEX %s ON %s (%s);",
-				b.quoteIdentifier(idx.Name), b.tableName, strings.Join(quotedColumns, ", "))
+	// Add check constraints
+	for _, check := range b.checks {
+		if b.dbDriver == "sqlite" {
+			b.addError(fmt.Errorf("sqlite cannot add check constraint %s to an existing table", check.Name))
+			continue
 		}
-		sqls = append(sqls, sql)
+		sqls = append(sqls, fmt.Sprintf("ALTER TABLE %s ADD %s;", b.tableName, b.checkToSQL(check)))
 	}
 
-	return strings.Join(sqls, "\n")
+	return sqls
 }
 
-// getModifyColumnSQL generates the appropriate MODIFY/CHANGE column SQL for the database
-func (b *Blueprint) getModifyColumnSQL(col Column) string {
+// modifyColumnStatements generates the MODIFY/CHANGE column statements for the database
+func (b *Blueprint) modifyColumnStatements(col Column) []string {
 	switch b.dbDriver {
 	case "mysql":
 		// MySQL uses MODIFY COLUMN
 		columnDef := b.columnToSQL(col)
-		return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", b.tableName, columnDef)
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", b.tableName, columnDef)}
 	case "postgres":
 		// PostgreSQL requires multiple statements for different aspects
 		var stmts []string
@@ -957,17 +1465,17 @@ func (b *Blueprint) getModifyColumnSQL(col Column) string {
 				b.tableName, b.quoteIdentifier(col.Name)))
 		}
 
-		return strings.Join(stmts, "\n")
+		return stmts
 	case "sqlite":
 		// SQLite doesn't support ALTER COLUMN in earlier versions
 		// Return a comment noting that a manual migration might be needed
 		// For newer SQLite (3.26.0+), we can use GENERATED ALWAYS but for compatibility,
 		// we'll document this limitation
-		return fmt.Sprintf("-- SQLite MODIFY not natively supported. Recommendation: Use raw SQL or recreate table.\n-- To modify %s: Rename table, create new one, copy data, drop old table",
-			b.quoteIdentifier(col.Name))
+		return []string{fmt.Sprintf("-- SQLite MODIFY not natively supported. Recommendation: Use raw SQL or recreate table.\n-- To modify %s: Rename table, create new one, copy data, drop old table",
+			b.quoteIdentifier(col.Name))}
 	default:
 		columnDef := b.columnToSQL(col)
-		return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", b.tableName, columnDef)
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", b.tableName, columnDef)}
 	}
 }
 
@@ -979,6 +1487,13 @@ func (b *Blueprint) columnToSQL(col Column) string {
 	dbType := b.getColumnType(col)
 	parts = append(parts, dbType)
 
+	// Generated column expression
+	if col.GeneratedAs != "" {
+		if generated := b.generatedColumnSQL(col); generated != "" {
+			parts = append(parts, generated)
+		}
+	}
+
 	// For "id" type, the database type already includes PRIMARY KEY, so skip NOT NULL
 	// PRIMARY KEY implicitly requires NOT NULL
 	if col.Type != "id" {
@@ -988,8 +1503,8 @@ func (b *Blueprint) columnToSQL(col Column) string {
 		}
 	}
 
-	// Default value
-	if col.Default != nil {
+	// Default value - generated columns can't have one
+	if col.Default != nil && col.GeneratedAs == "" {
 		if str, ok := col.Default.(string); ok {
 			// Special case for CURRENT_TIMESTAMP
 			if strings.ToUpper(str) == "CURRENT_TIMESTAMP" {
@@ -1004,6 +1519,15 @@ func (b *Blueprint) columnToSQL(col Column) string {
 		}
 	}
 
+	// ON UPDATE CURRENT_TIMESTAMP (MySQL only)
+	if col.OnUpdateCurrent {
+		if b.dbDriver == "mysql" {
+			parts = append(parts, "ON UPDATE CURRENT_TIMESTAMP")
+		} else {
+			b.addError(fmt.Errorf("%s does not support ON UPDATE CURRENT_TIMESTAMP (column %s); use a trigger or set it in the model", b.dbDriver, col.Name))
+		}
+	}
+
 	// Unique
 	if col.Unique {
 		parts = append(parts, "UNIQUE")
@@ -1029,9 +1553,46 @@ func (b *Blueprint) columnToSQL(col Column) string {
 		parts = append(parts, checkConstraint)
 	}
 
+	// Column placement (MySQL ALTER TABLE only)
+	if b.mode == "alter" {
+		if col.First {
+			parts = append(parts, "FIRST")
+		} else if col.After != "" {
+			parts = append(parts, "AFTER "+b.quoteIdentifier(col.After))
+		}
+	}
+
 	return strings.Join(parts, " ")
 }
 
+// generatedColumnSQL returns the GENERATED ALWAYS AS clause for a generated column
+func (b *Blueprint) generatedColumnSQL(col Column) string {
+	kind := "VIRTUAL"
+	if col.GeneratedStored {
+		kind = "STORED"
+	}
+
+	switch b.dbDriver {
+	case "postgres":
+		if !col.GeneratedStored {
+			b.addError(fmt.Errorf("postgres does not support virtual generated columns (column %s); use StoredAs", col.Name))
+			return ""
+		}
+	case "sqlite":
+		if col.GeneratedStored && b.mode == "alter" && !col.Modify {
+			b.addError(fmt.Errorf("sqlite cannot add stored generated column %s to an existing table; use VirtualAs", col.Name))
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", col.GeneratedAs, kind)
+}
+
+// checkToSQL converts a check constraint to SQL
+func (b *Blueprint) checkToSQL(check CheckConstraint) string {
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s)", b.quoteIdentifier(check.Name), check.Expression)
+}
+
 // getColumnType returns database-specific column type
 func (b *Blueprint) getColumnType(col Column) string {
 	typeMap := map[string]map[string]string{
@@ -1175,6 +1736,29 @@ func (b *Blueprint) getColumnType(col Column) string {
 			"postgres": "SMALLINT",
 			"sqlite":   "INTEGER",
 		},
+		"ipaddress": {
+			"mysql":    "VARCHAR(45)",
+			"postgres": "INET",
+			"sqlite":   "VARCHAR(45)",
+		},
+		"macaddress": {
+			"mysql":    "VARCHAR(17)",
+			"postgres": "MACADDR",
+			"sqlite":   "VARCHAR(17)",
+		},
+		"ulid": {
+			"mysql":    "CHAR(26)",
+			"postgres": "CHAR(26)",
+			"sqlite":   "CHAR(26)",
+		},
+		"jsonb": {
+			"mysql":    "JSON",
+			"postgres": "JSONB",
+			"sqlite":   "TEXT",
+		},
+		"tsvector": {
+			"postgres": "TSVECTOR",
+		},
 	}
 
 	// Handle enum type specially
@@ -1182,6 +1766,19 @@ func (b *Blueprint) getColumnType(col Column) string {
 		return b.getEnumType(col)
 	}
 
+	// Handle array type specially
+	if col.Type == "array" {
+		return b.getArrayType(col)
+	}
+
+	// Identity columns replace SERIAL on PostgreSQL
+	if col.Identity != "" && b.dbDriver == "postgres" {
+		return b.getIdentityType(col)
+	}
+	if col.Identity != "" && b.dbDriver == "sqlite" && col.Type != "id" {
+		b.addError(fmt.Errorf("sqlite only supports auto-incrementing primary keys, use ID() instead of Identity() on column %s", col.Name))
+	}
+
 	if dbTypes, exists := typeMap[col.Type]; exists {
 		if dbType, exists := dbTypes[b.dbDriver]; exists {
 			// Add UNSIGNED modifier for numeric types in MySQL
@@ -1192,6 +1789,16 @@ func (b *Blueprint) getColumnType(col Column) string {
 		}
 	}
 
+	// Custom column types registered with RegisterColumnType
+	if dbType, exists := b.customColumnType(col); exists {
+		return dbType
+	}
+
+	// Built-in type without an equivalent on this driver
+	if _, exists := typeMap[col.Type]; exists {
+		b.addError(fmt.Errorf("column type %s is not supported on %s (column %s)", col.Type, b.dbDriver, col.Name))
+	}
+
 	return col.Type // fallback to original type
 }
 
@@ -1236,6 +1843,43 @@ func (b *Blueprint) getEnumType(col Column) string {
 	}
 }
 
+// getArrayType returns database-specific array type
+func (b *Blueprint) getArrayType(col Column) string {
+	switch b.dbDriver {
+	case "postgres":
+		element := b.getColumnType(Column{Name: col.Name, Type: col.ElementType, Length: col.Length})
+		return element + "[]"
+	case "mysql":
+		// MySQL has no array type, store as JSON
+		return "JSON"
+	default:
+		// SQLite stores arrays as serialized TEXT
+		return "TEXT"
+	}
+}
+
+// getIdentityType returns the PostgreSQL identity column type
+func (b *Blueprint) getIdentityType(col Column) string {
+	var baseType string
+	switch col.Type {
+	case "id", "bigint":
+		baseType = "BIGINT"
+	case "integer", "mediumint":
+		baseType = "INTEGER"
+	case "smallint", "tinyint":
+		baseType = "SMALLINT"
+	default:
+		b.addError(fmt.Errorf("identity is only supported on integer columns (column %s is %s)", col.Name, col.Type))
+		return b.getColumnType(Column{Name: col.Name, Type: col.Type, Length: col.Length})
+	}
+
+	dbType := fmt.Sprintf("%s GENERATED %s AS IDENTITY", baseType, strings.ToUpper(col.Identity))
+	if col.Type == "id" {
+		dbType += " PRIMARY KEY"
+	}
+	return dbType
+}
+
 // indexToSQL converts an index to SQL
 func (b *Blueprint) indexToSQL(idx Index) string {
 	// Quote column names in the index
@@ -1244,14 +1888,101 @@ func (b *Blueprint) indexToSQL(idx Index) string {
 		quotedColumns[i] = b.quoteIdentifier(col)
 	}
 
+	b.validateIndex(idx)
+
+	using := ""
+	if idx.Method != "" && b.dbDriver == "mysql" {
+		using = " USING " + strings.ToUpper(idx.Method)
+	}
+
 	switch idx.Type {
 	case "primary":
 		return fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(quotedColumns, ", "))
 	case "unique":
-		return fmt.Sprintf("UNIQUE INDEX %s (%s)", b.quoteIdentifier(idx.Name), strings.Join(quotedColumns, ", "))
+		return fmt.Sprintf("UNIQUE INDEX %s%s (%s)", b.quoteIdentifier(idx.Name), using, strings.Join(quotedColumns, ", "))
 	default:
-		return fmt.Sprintf("INDEX %s (%s)", b.quoteIdentifier(idx.Name), strings.Join(quotedColumns, ", "))
+		return fmt.Sprintf("INDEX %s%s (%s)", b.quoteIdentifier(idx.Name), using, strings.Join(quotedColumns, ", "))
+	}
+}
+
+// createIndexSQL converts an index to a standalone CREATE INDEX statement
+func (b *Blueprint) createIndexSQL(idx Index) string {
+	quotedColumns := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		quotedColumns[i] = b.quoteIdentifier(col)
+	}
+
+	b.validateIndex(idx)
+
+	var sql strings.Builder
+	sql.WriteString("CREATE ")
+	if idx.Type == "unique" {
+		sql.WriteString("UNIQUE ")
+	}
+	sql.WriteString("INDEX ")
+	if idx.Concurrently && b.dbDriver == "postgres" {
+		sql.WriteString("CONCURRENTLY ")
+	}
+	sql.WriteString(fmt.Sprintf("%s ON %s", b.quoteIdentifier(idx.Name), b.tableName))
+
+	if idx.Method != "" && b.dbDriver == "postgres" {
+		sql.WriteString(" USING " + idx.Method)
+	}
+
+	sql.WriteString(fmt.Sprintf(" (%s)", strings.Join(quotedColumns, ", ")))
+
+	if idx.Method != "" && b.dbDriver == "mysql" {
+		sql.WriteString(" USING " + strings.ToUpper(idx.Method))
+	}
+
+	if idx.Where != "" && b.dbDriver != "mysql" {
+		sql.WriteString(" WHERE " + idx.Where)
+	}
+
+	sql.WriteString(";")
+	return sql.String()
+}
+
+// validateIndex records an error for index options the driver can't honour
+func (b *Blueprint) validateIndex(idx Index) {
+	switch b.dbDriver {
+	case "mysql":
+		if idx.Method != "" && idx.Method != "btree" && idx.Method != "hash" {
+			b.addError(fmt.Errorf("mysql does not support the %s index method (index %s)", idx.Method, idx.Name))
+		}
+		if idx.Where != "" {
+			b.addError(fmt.Errorf("mysql does not support partial indexes (index %s)", idx.Name))
+		}
+	case "postgres":
+		if idx.Method != "" && !slices.Contains([]string{"btree", "hash", "gin", "gist", "spgist", "brin"}, idx.Method) {
+			b.addError(fmt.Errorf("unknown postgres index method %s (index %s)", idx.Method, idx.Name))
+		}
+	case "sqlite":
+		if idx.Method != "" && idx.Method != "btree" {
+			b.addError(fmt.Errorf("sqlite does not support the %s index method (index %s)", idx.Method, idx.Name))
+		}
+	}
+}
+
+// takeConcurrentIndexes removes CONCURRENTLY indexes from the blueprint and
+// returns their statements, which PostgreSQL refuses to run inside a transaction
+func (b *Blueprint) takeConcurrentIndexes() []string {
+	if b.dbDriver != "postgres" {
+		return nil
+	}
+
+	var statements []string
+	remaining := make([]Index, 0, len(b.indexes))
+	for _, idx := range b.indexes {
+		if idx.Concurrently && idx.Type != "primary" {
+			statements = append(statements, b.createIndexSQL(idx))
+			continue
+		}
+		remaining = append(remaining, idx)
 	}
+	b.indexes = remaining
+
+	return statements
 }
 
 // foreignKeyToSQL converts a foreign key to SQL
@@ -0,0 +1,243 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type diffUser struct {
+	ID        uint
+	Name      string `gorm:"size:100;not null"`
+	Email     string `gorm:"size:191;uniqueIndex"`
+	Bio       string
+	Score     int32   `gorm:"default:0"`
+	Balance   float64 `gorm:"type:decimal(12,4)"`
+	Active    bool    `gorm:"default:true"`
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+func (diffUser) TableName() string {
+	return "diff_users"
+}
+
+func TestSchemaDiffCreatesMissingTable(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+
+	differ := &SchemaDiffer{db: db, dbDriver: "sqlite"}
+	diff, err := differ.Diff(&diffUser{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	up := diff.UpSource()
+	expected := []string{
+		`schema.Create("diff_users", func(table *database.Blueprint) {`,
+		`table.ID()`,
+		`table.String("name", 100).NotNullable()`,
+		`table.String("email", 191)`,
+		`table.Text("bio")`,
+		`table.Integer("score").Default(0)`,
+		`table.Decimal("balance", 12, 4)`,
+		`table.Boolean("active").Default(true)`,
+		`table.Timestamp("created_at")`,
+		`table.SoftDeletes()`,
+		`table.UniqueIndex([]string{"email"}, "idx_diff_users_email")`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(up, want) {
+			t.Errorf("Expected Up to contain %q, got:\n%s", want, up)
+		}
+	}
+
+	if down := diff.DownSource(); !strings.Contains(down, `schema.DropIfExists("diff_users")`) {
+		t.Errorf("Expected Down to drop the table, got:\n%s", down)
+	}
+}
+
+func TestSchemaDiffAltersExistingTable(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+
+	schema := &Schema{db: db, dbDriver: "sqlite"}
+	if err := schema.Create("diff_users", func(table *Blueprint) {
+		table.ID()
+		table.String("name", 100).NotNullable()
+		table.String("email", 191)
+		table.String("legacy")
+		table.UniqueIndex([]string{"email"}, "idx_diff_users_email")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	differ := &SchemaDiffer{db: db, dbDriver: "sqlite"}
+	diff, err := differ.Diff(&diffUser{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	up := diff.UpSource()
+	if !strings.Contains(up, `schema.Table("diff_users"`) {
+		t.Errorf("Expected Table call, got:\n%s", up)
+	}
+	if strings.Contains(up, `table.String("name"`) || strings.Contains(up, "UniqueIndex") {
+		t.Errorf("Existing columns and indexes should not be re-added, got:\n%s", up)
+	}
+	if !strings.Contains(up, `table.Text("bio")`) || !strings.Contains(up, `table.SoftDeletes()`) {
+		t.Errorf("Expected missing columns, got:\n%s", up)
+	}
+	if !strings.Contains(up, `table.DropColumn("legacy")`) {
+		t.Errorf("Expected Up to drop the extra column, got:\n%s", up)
+	}
+
+	down := diff.DownSource()
+	if !strings.Contains(down, `table.DropColumn("bio")`) || !strings.Contains(down, `table.DropSoftDeletes()`) {
+		t.Errorf("Expected Down to drop added columns, got:\n%s", down)
+	}
+	if !strings.Contains(down, `table.String("legacy", 255)`) {
+		t.Errorf("Expected Down to re-add the extra column, got:\n%s", down)
+	}
+
+	// Applying the generated calls brings the table in sync
+	if err := schema.Table("diff_users", func(table *Blueprint) {
+		table.Text("bio")
+		table.Integer("score").Default(0)
+		table.Decimal("balance", 12, 4)
+		table.Boolean("active").Default(true)
+		table.Timestamp("created_at")
+		table.SoftDeletes()
+		table.DropColumn("legacy")
+	}); err != nil {
+		t.Fatalf("Failed to alter table: %v", err)
+	}
+
+	diff, err = differ.Diff(&diffUser{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !diff.IsEmpty() {
+		t.Errorf("Expected schema to be in sync, got:\n%s", diff.UpSource())
+	}
+}
+
+func TestSchemaDiffModifiesChangedColumns(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+
+	schema := &Schema{db: db, dbDriver: "sqlite"}
+	if err := schema.Create("diff_users", func(table *Blueprint) {
+		table.ID()
+		table.String("name", 50)
+		table.Integer("email")
+		table.Text("bio")
+		table.Integer("score").Default(0)
+		table.Decimal("balance", 12, 4)
+		table.Boolean("active").Default(true)
+		table.Timestamp("created_at")
+		table.SoftDeletes()
+		table.UniqueIndex([]string{"email"}, "idx_diff_users_email")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	differ := &SchemaDiffer{db: db, dbDriver: "sqlite"}
+	diff, err := differ.Diff(&diffUser{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff.IsEmpty() {
+		t.Fatal("Expected changed columns to be reported")
+	}
+
+	up := diff.UpSource()
+	for _, want := range []string{
+		`table.Modify("name").String("name", 100).NotNullable()`,
+		`table.Modify("email").String("email", 191)`,
+		"SQLite cannot modify columns in place",
+	} {
+		if !strings.Contains(up, want) {
+			t.Errorf("Expected Up to contain %q, got:\n%s", want, up)
+		}
+	}
+	for _, unchanged := range []string{`Modify("bio")`, `Modify("score")`, `Modify("active")`, `Modify("created_at")`} {
+		if strings.Contains(up, unchanged) {
+			t.Errorf("Expected %s to be left alone, got:\n%s", unchanged, up)
+		}
+	}
+
+	down := diff.DownSource()
+	for _, want := range []string{
+		`table.Modify("name").String("name", 50)`,
+		`table.Modify("email").Integer("email")`,
+	} {
+		if !strings.Contains(down, want) {
+			t.Errorf("Expected Down to contain %q, got:\n%s", want, down)
+		}
+	}
+}
+
+type diffPost struct {
+	gorm.Model
+	Title string `gorm:"size:200;not null"`
+}
+
+func (diffPost) TableName() string {
+	return "diff_posts"
+}
+
+func TestSchemaDiffGormModel(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+
+	differ := &SchemaDiffer{db: db, dbDriver: "sqlite"}
+	diff, err := differ.Diff(&diffPost{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	up := diff.UpSource()
+	if !strings.Contains(up, `table.SoftDeletes()`) {
+		t.Errorf("Expected SoftDeletes, got:\n%s", up)
+	}
+	if strings.Contains(up, `"idx_diff_posts_deleted_at"`) {
+		t.Errorf("SoftDeletes already indexes deleted_at, got:\n%s", up)
+	}
+
+	// Applying the generated calls brings the table in sync
+	schema := &Schema{db: db, dbDriver: "sqlite"}
+	if err := schema.Create("diff_posts", func(table *Blueprint) {
+		table.ID()
+		table.Timestamp("created_at")
+		table.Timestamp("updated_at")
+		table.SoftDeletes()
+		table.String("title", 200).NotNullable()
+		table.String("zeta")
+		table.String("alpha")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	diff, err = differ.Diff(&diffPost{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Tables) != 1 || len(diff.Tables[0].Columns) > 0 || len(diff.Tables[0].Indexes) > 0 {
+		t.Errorf("Expected no columns or indexes to add, got:\n%s", diff.UpSource())
+	}
+	if extra := diff.Tables[0].ExtraColumns; strings.Join(extra, ",") != "alpha,zeta" {
+		t.Errorf("Expected sorted extra columns [alpha zeta], got %v", extra)
+	}
+}
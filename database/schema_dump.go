@@ -0,0 +1,264 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// squashedBatch is the batch of migrations rows whose files were pruned in
+// favour of the schema dump. They are never rolled back or reported as missing
+const squashedBatch = 0
+
+// SchemaDumpPath returns the schema dump file for a driver
+func SchemaDumpPath(driver string) string {
+	return filepath.Join("db", "schema", driver+"-schema.sql")
+}
+
+// SQLiteSchema returns the CREATE statements of a SQLite database
+func (m *Migrator) SQLiteSchema() (string, error) {
+	var statements []string
+	err := m.db.Raw(`SELECT sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%' AND name <> 'migrations_lock'
+		ORDER BY CASE type WHEN 'table' THEN 0 ELSE 1 END, name`).Scan(&statements).Error
+	if err != nil {
+		return "", err
+	}
+
+	var sql strings.Builder
+	for _, statement := range statements {
+		sql.WriteString(statement + ";\n")
+	}
+	return sql.String(), nil
+}
+
+// MigrationRowsSQL returns INSERT statements recreating the migrations rows with
+// their batch, or marked as squashed when their files are pruned
+func (m *Migrator) MigrationRowsSQL(squashed bool) (string, error) {
+	if err := m.CreateMigrationsTable(); err != nil {
+		return "", err
+	}
+
+	var records []MigrationInfo
+	if err := m.db.Table("migrations").Order("migration ASC").Find(&records).Error; err != nil {
+		return "", err
+	}
+
+	table := "migrations"
+	if m.schema.dbDriver == "postgres" {
+		// pg_dump empties search_path, so qualify the table with the current schema
+		var current string
+		if err := m.db.Raw("SELECT current_schema()").Scan(&current).Error; err != nil {
+			return "", err
+		}
+		table = `"` + strings.ReplaceAll(current, `"`, `""`) + `".migrations`
+	}
+
+	var sql strings.Builder
+	for _, record := range records {
+		batch := record.Batch
+		if squashed {
+			batch = squashedBatch
+		}
+		sql.WriteString(fmt.Sprintf("INSERT INTO %s (migration, batch, checksum, created_at) VALUES ('%s', %d, '%s', '%s');\n",
+			table,
+			strings.ReplaceAll(record.Migration, "'", "''"),
+			batch,
+			record.Checksum,
+			record.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+		))
+	}
+	return sql.String(), nil
+}
+
+// MarkSquashed moves every ran migration into the squashed batch, after their
+// files were pruned in favour of the schema dump
+func (m *Migrator) MarkSquashed() error {
+	return m.db.Table("migrations").Where("1 = 1").Update("batch", squashedBatch).Error
+}
+
+// LoadSchemaDump executes a schema dump file against the database
+func (m *Migrator) LoadSchemaDump(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Drop psql meta-commands such as \restrict emitted by recent pg_dump versions
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), `\`) {
+			lines = append(lines, line)
+		}
+	}
+	sql := strings.Join(lines, "\n")
+
+	switch m.schema.dbDriver {
+	case "mysql":
+		// The MySQL driver runs one statement per Exec
+		for _, statement := range splitSQLStatements(sql, true) {
+			if err := m.db.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to load schema dump: %w", err)
+			}
+		}
+		return nil
+	case "postgres":
+		// pg_dump changes session settings such as search_path, so load on a
+		// dedicated connection and reset it before it goes back to the pool
+		sqlDB, err := m.db.DB()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if _, err := conn.ExecContext(ctx, sql); err != nil {
+			return fmt.Errorf("failed to load schema dump: %w", err)
+		}
+		_, err = conn.ExecContext(ctx, "RESET ALL")
+		return err
+	default:
+		if err := m.db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to load schema dump: %w", err)
+		}
+		return nil
+	}
+}
+
+// loadSchemaDumpIfEmpty loads the schema dump when the database has no tables yet
+func (m *Migrator) loadSchemaDumpIfEmpty() (bool, error) {
+	path := m.schemaDumpPath
+	if path == "" {
+		path = SchemaDumpPath(m.schema.dbDriver)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return false, nil
+	}
+
+	tables, err := m.db.Migrator().GetTables()
+	if err != nil {
+		return false, err
+	}
+	for _, table := range tables {
+		if table != "migrations_lock" {
+			return false, nil
+		}
+	}
+
+	start := time.Now()
+	if err := m.LoadSchemaDump(path); err != nil {
+		return false, err
+	}
+	fmt.Printf("Loaded schema dump %s (%s)\n", path, time.Since(start).Round(time.Millisecond))
+
+	return true, nil
+}
+
+// splitSQLStatements splits a SQL script on semicolons outside quotes and comments
+func splitSQLStatements(sql string, mysql bool) []string {
+	var statements []string
+
+	scanner := newStatementScanner(bufio.NewReader(strings.NewReader(sql)), mysql)
+	for {
+		statement, err := scanner.Next()
+		if err != nil {
+			return statements
+		}
+		statements = append(statements, statement)
+	}
+}
+
+// statementScanner reads SQL statements one at a time from a script, so large
+// dumps are restored without loading them in memory
+type statementScanner struct {
+	reader *bufio.Reader
+	mysql  bool
+}
+
+// newStatementScanner creates a scanner. mysql enables MySQL string escapes and
+// # comments, which are operators such as #>> in PostgreSQL
+func newStatementScanner(reader *bufio.Reader, mysql bool) *statementScanner {
+	return &statementScanner{reader: reader, mysql: mysql}
+}
+
+// Next returns the next statement without its trailing semicolon, or io.EOF
+func (s *statementScanner) Next() (string, error) {
+	var (
+		current strings.Builder
+		quote   byte
+	)
+
+	for {
+		ch, err := s.reader.ReadByte()
+		if err == io.EOF {
+			if statement := strings.TrimSpace(current.String()); statement != "" {
+				return statement, nil
+			}
+			return "", io.EOF
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if quote != 0 {
+			current.WriteByte(ch)
+			if ch == '\\' && quote != '`' && s.mysql {
+				if next, err := s.reader.ReadByte(); err == nil {
+					current.WriteByte(next)
+				}
+			} else if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+			current.WriteByte(ch)
+		case ch == '-' && s.peek("- "), ch == '#' && s.mysql:
+			// Line comment
+			s.reader.ReadString('\n')
+			current.WriteByte('\n')
+		case ch == '/' && s.peek("*") && !s.peek("*!"):
+			// Block comment, MySQL /*! ... */ executable comments are kept
+			s.skipBlockComment()
+		case ch == ';':
+			if statement := strings.TrimSpace(current.String()); statement != "" {
+				return statement, nil
+			}
+			current.Reset()
+		default:
+			current.WriteByte(ch)
+		}
+	}
+}
+
+// peek reports whether the upcoming bytes equal prefix
+func (s *statementScanner) peek(prefix string) bool {
+	next, _ := s.reader.Peek(len(prefix))
+	return string(next) == prefix
+}
+
+// skipBlockComment consumes input up to and including the closing */
+func (s *statementScanner) skipBlockComment() {
+	s.reader.ReadByte() // the opening *
+	var previous byte
+	for {
+		ch, err := s.reader.ReadByte()
+		if err != nil || (previous == '*' && ch == '/') {
+			return
+		}
+		previous = ch
+	}
+}
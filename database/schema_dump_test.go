@@ -0,0 +1,113 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaDumpRoundTrip(t *testing.T) {
+	source, _ := newChecksumMigrator(t)
+
+	posts := &checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_posts_table", Timestamp: 1},
+		up:            createPostsTable("title"),
+	}
+	source.registry.Register(posts)
+
+	if err := source.Up(); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	schema, err := source.SQLiteSchema()
+	if err != nil {
+		t.Fatalf("Failed to dump schema: %v", err)
+	}
+	if rows, err := source.MigrationRowsSQL(false); err != nil || !strings.Contains(rows, "_create_posts_table', 1,") {
+		t.Errorf("Expected the rows to keep their batch without pruning, got %q (err %v)", rows, err)
+	}
+	rows, err := source.MigrationRowsSQL(true)
+	if err != nil {
+		t.Fatalf("Failed to dump migrations rows: %v", err)
+	}
+
+	dumpPath := filepath.Join(t.TempDir(), "sqlite-schema.sql")
+	if err := os.WriteFile(dumpPath, []byte(schema+"\n"+rows), 0644); err != nil {
+		t.Fatalf("Failed to write dump: %v", err)
+	}
+
+	// A fresh database where the posts migration file has been pruned
+	target, db := newChecksumMigrator(t)
+	target.schemaDumpPath = dumpPath
+	target.registry.Register(&checksumMigration{
+		BaseMigration: BaseMigration{Name: "create_tags_table", Timestamp: 2},
+		up: func(schema *Schema) error {
+			return schema.Create("tags", func(table *Blueprint) {
+				table.ID()
+			})
+		},
+	})
+
+	if err := target.Up(); err != nil {
+		t.Fatalf("Failed to migrate from dump: %v", err)
+	}
+
+	if !target.schema.HasTable("posts") || !target.schema.HasTable("tags") {
+		t.Fatal("Expected posts from the dump and tags from the newer migration")
+	}
+
+	var batches []int
+	db.Table("migrations").Order("migration ASC").Pluck("batch", &batches)
+	if !reflect.DeepEqual(batches, []int{squashedBatch, 1}) {
+		t.Errorf("Expected squashed batch for dumped rows, got: %v", batches)
+	}
+
+	report, err := target.Drift()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.HasDrift() {
+		t.Errorf("Squashed migrations should not be reported missing, got: %v", report)
+	}
+
+	// The dump is only loaded into an empty database
+	if loaded, err := target.loadSchemaDumpIfEmpty(); err != nil || loaded {
+		t.Errorf("Expected dump to be skipped on a migrated database, loaded=%v err=%v", loaded, err)
+	}
+}
+
+func TestSplitSQLStatements(t *testing.T) {
+	sql := `/*!40101 SET NAMES utf8mb4 */;
+-- comment; with a semicolon
+CREATE TABLE notes (body VARCHAR(255) DEFAULT 'a;b');
+/* block; comment */
+INSERT INTO notes (body) VALUES ("it's; fine");`
+
+	expected := []string{
+		"/*!40101 SET NAMES utf8mb4 */",
+		"CREATE TABLE notes (body VARCHAR(255) DEFAULT 'a;b')",
+		`INSERT INTO notes (body) VALUES ("it's; fine")`,
+	}
+
+	if got := splitSQLStatements(sql, true); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected statements:\n%q", got)
+	}
+}
+
+func TestSplitSQLStatementsHashComments(t *testing.T) {
+	mysql := "# dumped by mysqldump; version 8\nCREATE TABLE notes (id INT);"
+	if got := splitSQLStatements(mysql, true); !reflect.DeepEqual(got, []string{"CREATE TABLE notes (id INT)"}) {
+		t.Errorf("Expected # to start a MySQL comment, got:\n%q", got)
+	}
+
+	postgres := "CREATE VIEW leaves AS SELECT data #>> '{a,b}' AS leaf FROM docs;\nSELECT 1;"
+	expected := []string{
+		"CREATE VIEW leaves AS SELECT data #>> '{a,b}' AS leaf FROM docs",
+		"SELECT 1",
+	}
+	if got := splitSQLStatements(postgres, false); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected #>> to be kept on PostgreSQL, got:\n%q", got)
+	}
+}
@@ -0,0 +1,200 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestGeneratedColumns(t *testing.T) {
+	tests := []struct {
+		dbType   string
+		contains []string
+	}{
+		{"mysql", []string{
+			"total DECIMAL(10,2) GENERATED ALWAYS AS (price * quantity) STORED",
+			"full_name VARCHAR(255) GENERATED ALWAYS AS (CONCAT(first_name, ' ', last_name)) VIRTUAL",
+		}},
+		{"sqlite", []string{
+			"total REAL GENERATED ALWAYS AS (price * quantity) STORED",
+			"full_name VARCHAR(255) GENERATED ALWAYS AS (CONCAT(first_name, ' ', last_name)) VIRTUAL",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType, func(t *testing.T) {
+			blueprint := NewBlueprint("orders", tt.dbType)
+			blueprint.Decimal("total", 10, 2).StoredAs("price * quantity")
+			blueprint.String("full_name").VirtualAs("CONCAT(first_name, ' ', last_name)")
+
+			sql := blueprint.ToSQL()
+
+			for _, want := range tt.contains {
+				if !strings.Contains(sql, want) {
+					t.Errorf("[%s] Expected SQL to contain %q, got: %s", tt.dbType, want, sql)
+				}
+			}
+			if err := blueprint.Err(); err != nil {
+				t.Errorf("[%s] Expected no error, got: %v", tt.dbType, err)
+			}
+		})
+	}
+}
+
+func TestGeneratedColumnSkipsDefault(t *testing.T) {
+	blueprint := NewBlueprint("users", "mysql")
+	blueprint.Boolean("is_adult").StoredAs("age >= 18")
+
+	sql := blueprint.ToSQL()
+
+	if strings.Contains(sql, "DEFAULT") {
+		t.Errorf("Generated column should not have a DEFAULT, got: %s", sql)
+	}
+}
+
+func TestGeneratedColumnUnsupported(t *testing.T) {
+	postgres := NewBlueprint("orders", "postgres")
+	postgres.String("full_name").VirtualAs("first_name || ' ' || last_name")
+	postgres.ToSQL()
+
+	if err := postgres.Err(); err == nil || !strings.Contains(err.Error(), "virtual generated columns") {
+		t.Errorf("Expected virtual column error on postgres, got: %v", err)
+	}
+
+	sqliteAlter := NewBlueprint("orders", "sqlite")
+	sqliteAlter.SetMode("alter")
+	sqliteAlter.Integer("total").StoredAs("price * quantity")
+	sqliteAlter.ToSQL()
+
+	if err := sqliteAlter.Err(); err == nil || !strings.Contains(err.Error(), "stored generated column") {
+		t.Errorf("Expected stored column error on sqlite alter, got: %v", err)
+	}
+}
+
+func TestCheckConstraints(t *testing.T) {
+	create := NewBlueprint("products", "postgres")
+	create.Decimal("price", 10, 2)
+	create.Check("chk_products_price", "price >= 0")
+
+	sql := create.ToSQL()
+	if !strings.Contains(sql, "CONSTRAINT chk_products_price CHECK (price >= 0)") {
+		t.Errorf("Expected inline check constraint, got: %s", sql)
+	}
+
+	alter := NewBlueprint("products", "mysql")
+	alter.SetMode("alter")
+	alter.Check("chk_products_price", "price >= 0")
+	alter.DropCheck("chk_products_old")
+
+	sql = alter.ToSQL()
+	if !strings.Contains(sql, "ALTER TABLE products ADD CONSTRAINT chk_products_price CHECK (price >= 0);") {
+		t.Errorf("Expected ADD CONSTRAINT, got: %s", sql)
+	}
+	if !strings.Contains(sql, "ALTER TABLE products DROP CHECK chk_products_old;") {
+		t.Errorf("Expected DROP CHECK, got: %s", sql)
+	}
+
+	sqliteAlter := NewBlueprint("products", "sqlite")
+	sqliteAlter.SetMode("alter")
+	sqliteAlter.Check("chk_products_price", "price >= 0")
+	sqliteAlter.ToSQL()
+
+	if err := sqliteAlter.Err(); err == nil {
+		t.Errorf("Expected error adding a check constraint on sqlite alter")
+	}
+}
+
+func TestColumnPlacement(t *testing.T) {
+	blueprint := NewBlueprint("users", "mysql")
+	blueprint.SetMode("alter")
+	blueprint.String("middle_name").After("first_name")
+	blueprint.BigInteger("tenant_id").First()
+
+	sql := blueprint.ToSQL()
+
+	if !strings.Contains(sql, "ADD COLUMN middle_name VARCHAR(255) AFTER first_name;") {
+		t.Errorf("Expected AFTER placement, got: %s", sql)
+	}
+	if !strings.Contains(sql, "ADD COLUMN tenant_id BIGINT FIRST;") {
+		t.Errorf("Expected FIRST placement, got: %s", sql)
+	}
+
+	postgres := NewBlueprint("users", "postgres")
+	postgres.SetMode("alter")
+	postgres.String("middle_name").After("first_name")
+
+	sql = postgres.ToSQL()
+	if strings.Contains(sql, "AFTER") {
+		t.Errorf("Postgres should not emit column placement, got: %s", sql)
+	}
+	if err := postgres.Err(); err == nil {
+		t.Error("Expected an error for column placement on postgres")
+	}
+
+	sqlite := NewBlueprint("users", "sqlite")
+	sqlite.SetMode("alter")
+	sqlite.BigInteger("tenant_id").First()
+	if err := sqlite.Err(); err == nil {
+		t.Error("Expected an error for column placement on sqlite")
+	}
+}
+
+func TestUseCurrentOnUpdate(t *testing.T) {
+	blueprint := NewBlueprint("posts", "mysql")
+	blueprint.Timestamp("updated_at").UseCurrent().UseCurrentOnUpdate()
+
+	sql := blueprint.ToSQL()
+	if !strings.Contains(sql, "updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP") {
+		t.Errorf("Expected ON UPDATE CURRENT_TIMESTAMP, got: %s", sql)
+	}
+
+	postgres := NewBlueprint("posts", "postgres")
+	postgres.Timestamp("updated_at").UseCurrentOnUpdate()
+	postgres.ToSQL()
+
+	if err := postgres.Err(); err == nil {
+		t.Errorf("Expected error for ON UPDATE on postgres")
+	}
+}
+
+func TestGeneratedAndCheckOnSQLite(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+
+	schema := &Schema{db: db, dbDriver: "sqlite"}
+
+	if err := schema.Create("line_items", func(table *Blueprint) {
+		table.ID()
+		table.Integer("price").NotNullable()
+		table.Integer("quantity").NotNullable()
+		table.Integer("total").StoredAs("price * quantity")
+		table.Check("chk_line_items_quantity", "quantity > 0")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	if err := db.Exec("INSERT INTO line_items (price, quantity) VALUES (5, 3)").Error; err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+
+	var total int
+	db.Raw("SELECT total FROM line_items").Scan(&total)
+	if total != 15 {
+		t.Errorf("Expected generated total 15, got %d", total)
+	}
+
+	if err := db.Exec("INSERT INTO line_items (price, quantity) VALUES (5, 0)").Error; err == nil {
+		t.Errorf("Expected check constraint violation")
+	}
+
+	err = schema.Table("line_items", func(table *Blueprint) {
+		table.Check("chk_line_items_price", "price > 0")
+	})
+	if err == nil {
+		t.Errorf("Expected Schema.Table to return blueprint error")
+	}
+}
@@ -0,0 +1,211 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSoftDeletes(t *testing.T) {
+	blueprint := NewBlueprint("posts", "postgres")
+	blueprint.ID()
+	blueprint.SoftDeletes()
+
+	sql := blueprint.ToSQL()
+
+	if !strings.Contains(sql, "deleted_at TIMESTAMP") {
+		t.Errorf("Expected nullable deleted_at column, got: %s", sql)
+	}
+	if strings.Contains(sql, "deleted_at TIMESTAMP NOT NULL") {
+		t.Errorf("deleted_at should be nullable, got: %s", sql)
+	}
+	if !strings.Contains(sql, "CREATE INDEX idx_posts_deleted_at ON posts (deleted_at);") {
+		t.Errorf("Expected deleted_at index, got: %s", sql)
+	}
+}
+
+func TestDropSoftDeletes(t *testing.T) {
+	blueprint := NewBlueprint("posts", "mysql")
+	blueprint.SetMode("alter")
+	blueprint.DropSoftDeletes()
+
+	sql := blueprint.ToSQL()
+
+	if !strings.Contains(sql, "DROP INDEX idx_posts_deleted_at ON posts;") {
+		t.Errorf("Expected index drop, got: %s", sql)
+	}
+	if !strings.Contains(sql, "ALTER TABLE posts DROP COLUMN deleted_at;") {
+		t.Errorf("Expected column drop, got: %s", sql)
+	}
+	if strings.Index(sql, "DROP INDEX") > strings.Index(sql, "DROP COLUMN") {
+		t.Errorf("Index should be dropped before the column, got: %s", sql)
+	}
+}
+
+func TestForeignIDConstrained(t *testing.T) {
+	blueprint := NewBlueprint("posts", "mysql")
+	blueprint.ID()
+	blueprint.ForeignID("user_id").NotNullable().Constrained().CascadeOnDelete()
+	blueprint.ForeignID("category_id").Nullable().Constrained().NullOnDelete()
+	blueprint.ForeignID("author_id").Constrained("users", "uuid")
+
+	sql := blueprint.ToSQL()
+
+	expected := []string{
+		"user_id BIGINT UNSIGNED NOT NULL",
+		"FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE",
+		"FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE SET NULL",
+		"FOREIGN KEY (author_id) REFERENCES users (uuid)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(sql, want) {
+			t.Errorf("Expected SQL to contain %q, got: %s", want, sql)
+		}
+	}
+
+	if len(blueprint.foreign) != 3 {
+		t.Errorf("Expected 3 foreign keys, got %d", len(blueprint.foreign))
+	}
+}
+
+func TestForeignIDConstrainedAlter(t *testing.T) {
+	blueprint := NewBlueprint("posts", "postgres")
+	blueprint.SetMode("alter")
+	blueprint.ForeignID("user_id").Constrained().CascadeOnDelete()
+
+	sql := blueprint.ToSQL()
+
+	if !strings.Contains(sql, "ALTER TABLE posts ADD COLUMN user_id BIGINT") {
+		t.Errorf("Expected user_id column, got: %s", sql)
+	}
+	want := "ALTER TABLE posts ADD CONSTRAINT posts_user_id_foreign FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;"
+	if !strings.Contains(sql, want) {
+		t.Errorf("Expected SQL to contain %q, got: %s", want, sql)
+	}
+	if strings.Index(sql, "ADD CONSTRAINT") < strings.Index(sql, "ADD COLUMN") {
+		t.Errorf("Constraint should be added after the column, got: %s", sql)
+	}
+
+	sqliteBlueprint := NewBlueprint("posts", "sqlite")
+	sqliteBlueprint.SetMode("alter")
+	sqliteBlueprint.ForeignID("user_id").Constrained()
+	sqliteBlueprint.ToSQL()
+
+	if sqliteBlueprint.Err() == nil {
+		t.Error("Expected sqlite to record an error for a foreign key on an existing table")
+	}
+}
+
+func TestMorphs(t *testing.T) {
+	blueprint := NewBlueprint("comments", "mysql")
+	blueprint.ID()
+	blueprint.Morphs("commentable")
+
+	sql := blueprint.ToSQL()
+
+	expected := []string{
+		"commentable_type VARCHAR(255) NOT NULL",
+		"commentable_id BIGINT UNSIGNED NOT NULL",
+		"INDEX idx_comments_commentable_type_commentable_id (commentable_type, commentable_id)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(sql, want) {
+			t.Errorf("Expected SQL to contain %q, got: %s", want, sql)
+		}
+	}
+
+	drop := NewBlueprint("comments", "postgres")
+	drop.SetMode("alter")
+	drop.DropMorphs("commentable")
+	dropSQL := drop.ToSQL()
+
+	for _, want := range []string{
+		"DROP INDEX idx_comments_commentable_type_commentable_id;",
+		"DROP COLUMN commentable_type;",
+		"DROP COLUMN commentable_id;",
+	} {
+		if !strings.Contains(dropSQL, want) {
+			t.Errorf("Expected SQL to contain %q, got: %s", want, dropSQL)
+		}
+	}
+}
+
+func TestSpecialColumnTypes(t *testing.T) {
+	tests := []struct {
+		dbType   string
+		contains []string
+	}{
+		{"mysql", []string{"remember_token VARCHAR(100)", "ip_address VARCHAR(45)", "mac_address VARCHAR(17)", "ulid CHAR(26)"}},
+		{"postgres", []string{"remember_token VARCHAR(100)", "ip_address INET", "mac_address MACADDR", "ulid CHAR(26)"}},
+		{"sqlite", []string{"remember_token VARCHAR(100)", "ip_address VARCHAR(45)", "mac_address VARCHAR(17)", "ulid CHAR(26)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType, func(t *testing.T) {
+			blueprint := NewBlueprint("sessions", tt.dbType)
+			blueprint.RememberToken()
+			blueprint.IPAddress()
+			blueprint.MacAddress()
+			blueprint.ULID()
+
+			sql := blueprint.ToSQL()
+
+			for _, want := range tt.contains {
+				if !strings.Contains(sql, want) {
+					t.Errorf("[%s] Expected SQL to contain %q, got: %s", tt.dbType, want, sql)
+				}
+			}
+		})
+	}
+}
+
+func TestColumnHelpersOnSQLite(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+
+	schema := &Schema{db: db, dbDriver: "sqlite"}
+
+	if err := schema.Create("users", func(table *Blueprint) {
+		table.ID()
+		table.String("email").NotNullable()
+		table.RememberToken()
+		table.SoftDeletes()
+	}); err != nil {
+		t.Fatalf("Failed to create users table: %v", err)
+	}
+
+	if err := schema.Create("comments", func(table *Blueprint) {
+		table.ID()
+		table.ForeignID("user_id").Constrained().CascadeOnDelete()
+		table.Morphs("commentable")
+		table.IPAddress()
+		table.ULID()
+	}); err != nil {
+		t.Fatalf("Failed to create comments table: %v", err)
+	}
+
+	for _, column := range []string{"user_id", "commentable_type", "commentable_id", "ip_address", "ulid"} {
+		if !schema.HasColumn("comments", column) {
+			t.Errorf("Expected column %s on comments", column)
+		}
+	}
+
+	if err := schema.Table("users", func(table *Blueprint) {
+		table.DropSoftDeletes()
+	}); err != nil {
+		t.Fatalf("Failed to drop soft deletes: %v", err)
+	}
+
+	if schema.HasColumn("users", "deleted_at") {
+		t.Errorf("Expected deleted_at to be dropped")
+	}
+
+	// SQLite has no inline INDEX, which is why indexes are separate statements
+	if err := db.Exec("CREATE TABLE legacy (id INTEGER, INDEX idx_legacy_id (id))").Error; err == nil {
+		t.Error("Expected SQLite to reject an inline INDEX definition")
+	}
+}
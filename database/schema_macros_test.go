@@ -0,0 +1,89 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBlueprintMacro(t *testing.T) {
+	Macro("auditable", func(table *Blueprint, args ...any) {
+		table.ForeignID("created_by").Nullable()
+		table.ForeignID("updated_by").Nullable()
+		if len(args) > 0 && args[0] == true {
+			table.SoftDeletes()
+		}
+	})
+	defer delete(MacroRegistry, "auditable")
+
+	if !HasMacro("auditable") {
+		t.Fatal("Expected auditable macro to be registered")
+	}
+
+	blueprint := NewBlueprint("invoices", "mysql")
+	blueprint.ID()
+	blueprint.Macro("auditable", true)
+
+	sql := blueprint.ToSQL()
+
+	for _, want := range []string{"created_by BIGINT UNSIGNED", "updated_by BIGINT UNSIGNED", "deleted_at TIMESTAMP"} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("Expected SQL to contain %q, got: %s", want, sql)
+		}
+	}
+
+	if err := blueprint.Err(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestUnknownMacro(t *testing.T) {
+	blueprint := NewBlueprint("invoices", "mysql")
+	blueprint.Macro("missing")
+
+	if err := blueprint.Err(); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Expected unknown macro error, got: %v", err)
+	}
+}
+
+func TestCustomColumnType(t *testing.T) {
+	RegisterColumnType(ColumnType{
+		Name:    "citext",
+		Drivers: map[string]string{"postgres": "CITEXT"},
+	})
+	RegisterColumnType(ColumnType{
+		Name: "vector",
+		Resolve: func(col Column, driver string) string {
+			if driver == "postgres" {
+				return fmt.Sprintf("VECTOR(%d)", col.Length)
+			}
+			return ""
+		},
+	})
+	defer delete(ColumnTypeRegistry, "citext")
+	defer delete(ColumnTypeRegistry, "vector")
+
+	blueprint := NewBlueprint("documents", "postgres")
+	blueprint.AddColumn("citext", "search")
+	blueprint.AddColumn("vector", "embedding", 1536)
+
+	sql := blueprint.ToSQL()
+
+	if !strings.Contains(sql, "search CITEXT") {
+		t.Errorf("Expected CITEXT column, got: %s", sql)
+	}
+	if !strings.Contains(sql, "embedding VECTOR(1536)") {
+		t.Errorf("Expected VECTOR(1536) column, got: %s", sql)
+	}
+	if err := blueprint.Err(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	mysql := NewBlueprint("documents", "mysql")
+	mysql.AddColumn("citext", "search")
+	mysql.ToSQL()
+
+	if err := mysql.Err(); err == nil || !strings.Contains(err.Error(), "not supported on mysql") {
+		t.Errorf("Expected unsupported driver error, got: %v", err)
+	}
+}
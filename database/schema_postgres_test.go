@@ -0,0 +1,181 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPostgresColumnTypes(t *testing.T) {
+	tests := []struct {
+		dbType   string
+		contains []string
+	}{
+		{"postgres", []string{"data JSONB", "tags TEXT[]", "codes VARCHAR(10)[]", "ip INET"}},
+		{"mysql", []string{"data JSON", "tags JSON", "codes JSON", "ip VARCHAR(45)"}},
+		{"sqlite", []string{"data TEXT", "tags TEXT", "codes TEXT", "ip VARCHAR(45)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType, func(t *testing.T) {
+			blueprint := NewBlueprint("events", tt.dbType)
+			blueprint.JSONB("data")
+			blueprint.Array("tags", "text")
+			blueprint.Array("codes", "string", 10)
+			blueprint.Inet("ip")
+
+			sql := blueprint.ToSQL()
+
+			for _, want := range tt.contains {
+				if !strings.Contains(sql, want) {
+					t.Errorf("[%s] Expected SQL to contain %q, got: %s", tt.dbType, want, sql)
+				}
+			}
+			if err := blueprint.Err(); err != nil {
+				t.Errorf("[%s] Expected no error, got: %v", tt.dbType, err)
+			}
+		})
+	}
+}
+
+func TestTsVectorUnsupported(t *testing.T) {
+	postgres := NewBlueprint("documents", "postgres")
+	postgres.TsVector("search")
+
+	if sql := postgres.ToSQL(); !strings.Contains(sql, "search TSVECTOR") {
+		t.Errorf("Expected TSVECTOR column, got: %s", sql)
+	}
+
+	mysql := NewBlueprint("documents", "mysql")
+	mysql.TsVector("search")
+	mysql.ToSQL()
+
+	if err := mysql.Err(); err == nil || !strings.Contains(err.Error(), "tsvector is not supported on mysql") {
+		t.Errorf("Expected unsupported type error, got: %v", err)
+	}
+}
+
+func TestIndexMethodsAndPartialIndexes(t *testing.T) {
+	blueprint := NewBlueprint("documents", "postgres")
+	blueprint.JSONB("data")
+	blueprint.TsVector("search")
+	blueprint.String("email")
+	blueprint.Timestamp("deleted_at")
+	blueprint.Index([]string{"data"}).Using("gin")
+	blueprint.Index([]string{"search"}).Using("gist")
+	blueprint.UniqueIndex([]string{"email"}).Where("deleted_at IS NULL")
+
+	sql := blueprint.ToSQL()
+
+	expected := []string{
+		"CREATE INDEX idx_documents_data ON documents USING gin (data);",
+		"CREATE INDEX idx_documents_search ON documents USING gist (search);",
+		"CREATE UNIQUE INDEX unique_documents_email ON documents (email) WHERE deleted_at IS NULL;",
+	}
+	for _, want := range expected {
+		if !strings.Contains(sql, want) {
+			t.Errorf("Expected SQL to contain %q, got: %s", want, sql)
+		}
+	}
+
+	sqlite := NewBlueprint("documents", "sqlite")
+	sqlite.SetMode("alter")
+	sqlite.Index([]string{"email"}).Where("deleted_at IS NULL")
+
+	if sql := sqlite.ToSQL(); !strings.Contains(sql, "WHERE deleted_at IS NULL") {
+		t.Errorf("Expected SQLite partial index, got: %s", sql)
+	}
+}
+
+func TestIndexOptionsUnsupported(t *testing.T) {
+	mysql := NewBlueprint("documents", "mysql")
+	mysql.Index([]string{"data"}).Using("gin")
+	mysql.Index([]string{"email"}).Where("deleted_at IS NULL")
+	mysql.ToSQL()
+
+	err := mysql.Err()
+	if err == nil {
+		t.Fatal("Expected errors for gin and partial index on mysql")
+	}
+	if !strings.Contains(err.Error(), "gin index method") || !strings.Contains(err.Error(), "partial indexes") {
+		t.Errorf("Expected both index errors, got: %v", err)
+	}
+
+	hash := NewBlueprint("documents", "mysql")
+	hash.SetMode("alter")
+	hash.Index([]string{"token"}).Using("hash")
+
+	if sql := hash.ToSQL(); !strings.Contains(sql, "CREATE INDEX idx_documents_token ON documents (token) USING HASH;") {
+		t.Errorf("Expected USING HASH, got: %s", sql)
+	}
+}
+
+func TestConcurrentIndexRunsOutsideTransaction(t *testing.T) {
+	blueprint := NewBlueprint("documents", "postgres")
+	blueprint.SetMode("alter")
+	blueprint.String("slug")
+	blueprint.Index([]string{"slug"}).Concurrently()
+
+	concurrent := blueprint.takeConcurrentIndexes()
+	sql := blueprint.ToSQL()
+
+	if len(concurrent) != 1 || concurrent[0] != "CREATE INDEX CONCURRENTLY idx_documents_slug ON documents (slug);" {
+		t.Errorf("Expected one concurrent index statement, got: %v", concurrent)
+	}
+	if strings.Contains(sql, "CREATE INDEX") {
+		t.Errorf("Concurrent index should not be part of the transactional SQL, got: %s", sql)
+	}
+
+	schema := &Schema{dbDriver: "postgres", inTransaction: true}
+	if err := schema.execOutsideTransaction(concurrent); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(schema.deferred) != 1 {
+		t.Errorf("Expected statement to be deferred until after commit, got: %v", schema.deferred)
+	}
+
+	mysql := NewBlueprint("documents", "mysql")
+	mysql.SetMode("alter")
+	mysql.Index([]string{"slug"}).Concurrently()
+
+	if statements := mysql.takeConcurrentIndexes(); len(statements) != 0 {
+		t.Errorf("MySQL should keep the index inline, got: %v", statements)
+	}
+	if sql := mysql.ToSQL(); strings.Contains(sql, "CONCURRENTLY") {
+		t.Errorf("MySQL should not use CONCURRENTLY, got: %s", sql)
+	}
+}
+
+func TestIdentityColumns(t *testing.T) {
+	postgres := NewBlueprint("accounts", "postgres")
+	postgres.ID().Identity()
+	postgres.BigInteger("external_id").IdentityAlways()
+
+	sql := postgres.ToSQL()
+
+	if !strings.Contains(sql, "id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY") {
+		t.Errorf("Expected identity primary key, got: %s", sql)
+	}
+	if strings.Contains(sql, "BIGSERIAL") {
+		t.Errorf("Identity should replace BIGSERIAL, got: %s", sql)
+	}
+	if !strings.Contains(sql, "external_id BIGINT GENERATED ALWAYS AS IDENTITY NOT NULL") {
+		t.Errorf("Expected always identity column, got: %s", sql)
+	}
+
+	mysql := NewBlueprint("accounts", "mysql")
+	mysql.ID().Identity()
+	mysql.BigInteger("sequence").Identity()
+
+	sql = mysql.ToSQL()
+	if !strings.Contains(sql, "sequence BIGINT NOT NULL AUTO_INCREMENT") {
+		t.Errorf("Expected AUTO_INCREMENT fallback, got: %s", sql)
+	}
+
+	sqlite := NewBlueprint("accounts", "sqlite")
+	sqlite.BigInteger("sequence").Identity()
+	sqlite.ToSQL()
+
+	if err := sqlite.Err(); err == nil {
+		t.Errorf("Expected error for identity on a non primary key sqlite column")
+	}
+}
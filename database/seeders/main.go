@@ -2,9 +2,14 @@ package seeders
 
 import (
 	"fmt"
-	"os"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/galaplate/core/config"
+	"github.com/galaplate/core/database"
 	"gorm.io/gorm"
 )
 
@@ -12,56 +17,272 @@ type Seeder interface {
 	Seed(db *gorm.DB) error
 }
 
+// ConnectionSeeder is implemented by seeders that target a named connection
+// from database.connections instead of the one passed to Run
+type ConnectionSeeder interface {
+	GetConnection() string
+}
+
+// DependentSeeder is implemented by seeders that need other registered seeders
+// to run first, e.g. users before posts. Dependencies are added to the run even
+// when only the dependent seeder is selected
+type DependentSeeder interface {
+	Dependencies() []string
+}
+
+// EnvironmentSeeder is implemented by seeders limited to some app.env values,
+// e.g. demo data that must never reach production. An empty list runs everywhere
+type EnvironmentSeeder interface {
+	Environments() []string
+}
+
+// TransactionalSeeder is implemented by seeders that choose whether Run wraps
+// them in a transaction. Without it, seeders run in one. Embed
+// database.WithoutTransaction to opt out
+type TransactionalSeeder interface {
+	WithinTransaction() bool
+}
+
+// SeederRecord is a row of the seeders table, recording when a seeder last ran
+type SeederRecord struct {
+	ID    uint      `gorm:"primaryKey"`
+	Name  string    `gorm:"size:255;not null;uniqueIndex"`
+	RanAt time.Time `gorm:"not null"`
+}
+
+func (SeederRecord) TableName() string {
+	return "seeders"
+}
+
+// namedSeeder is a seeder with the name it is registered under
+type namedSeeder struct {
+	name   string
+	seeder Seeder
+}
+
 type DatabaseSeeder struct {
-	seeders []Seeder
+	seeders []namedSeeder
+
+	// Once skips seeders already recorded in the seeders table
+	Once bool
+
+	// Environment is checked against EnvironmentSeeder, defaulting to app.env
+	Environment string
+
+	// err is the selection error of NewDatabaseSeeder, returned by Run
+	err error
 }
 
+// AddSeeder adds a seeder named after its type, e.g. userseeder for
+// seeders.UserSeeder, the name make:seeder registers it under
 func (ds *DatabaseSeeder) AddSeeder(seeder Seeder) {
-	ds.seeders = append(ds.seeders, seeder)
+	ds.AddNamedSeeder(seederName(seeder), seeder)
+}
+
+// AddNamedSeeder adds a seeder under the name recorded in the seeders table.
+// Names are case-insensitive like RegisterSeeder
+func (ds *DatabaseSeeder) AddNamedSeeder(name string, seeder Seeder) {
+	ds.seeders = append(ds.seeders, namedSeeder{name: strings.ToLower(name), seeder: seeder})
+}
+
+// seederName returns the lower case type name of a seeder
+func seederName(seeder Seeder) string {
+	t := reflect.TypeOf(seeder)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return strings.ToLower(t.Name())
+}
+
+// Names returns the seeders in the order they run
+func (ds *DatabaseSeeder) Names() []string {
+	names := make([]string, len(ds.seeders))
+	for i, s := range ds.seeders {
+		names[i] = s.name
+	}
+	return names
 }
 
 func (ds *DatabaseSeeder) Run(db *gorm.DB) error {
+	if ds.err != nil {
+		return ds.err
+	}
+
 	fmt.Println("Starting database seeding...")
 
-	for _, seeder := range ds.seeders {
-		seederName := fmt.Sprintf("%T", seeder)
-		fmt.Printf("Running %s...\n", seederName)
+	if err := createSeedersTable(db); err != nil {
+		return fmt.Errorf("failed to create seeders table: %w", err)
+	}
 
-		if err := seeder.Seed(db); err != nil {
-			return fmt.Errorf("seeder %s failed: %w", seederName, err)
+	ran := map[string]bool{}
+	if ds.Once {
+		var names []string
+		if err := db.Model(&SeederRecord{}).Pluck("name", &names).Error; err != nil {
+			return fmt.Errorf("failed to read seeders table: %w", err)
+		}
+		for _, name := range names {
+			ran[name] = true
 		}
+	}
 
-		fmt.Printf("%s completed successfully\n", seederName)
+	env := ds.Environment
+	if env == "" {
+		env = config.ConfigString("app.env")
+	}
+
+	for _, s := range ds.seeders {
+		if guarded, ok := s.seeder.(EnvironmentSeeder); ok {
+			if envs := guarded.Environments(); len(envs) > 0 && !slices.Contains(envs, env) {
+				fmt.Printf("Skipping %s, it only runs in %s\n", s.name, strings.Join(envs, ", "))
+				continue
+			}
+		}
+		if ran[s.name] {
+			fmt.Printf("Skipping %s, already seeded\n", s.name)
+			continue
+		}
+
+		fmt.Printf("Running %s...\n", s.name)
+
+		if err := ds.runSeeder(db, s); err != nil {
+			return fmt.Errorf("seeder %s failed: %w", s.name, err)
+		}
+
+		fmt.Printf("%s completed successfully\n", s.name)
 	}
 
 	fmt.Println("Database seeding completed!")
 	return nil
 }
 
+// runSeeder seeds and records a seeder. The record is written in the seeder's
+// transaction when it seeds the connection holding the seeders table
+func (ds *DatabaseSeeder) runSeeder(db *gorm.DB, s namedSeeder) error {
+	seederDB := db
+	if target, ok := s.seeder.(ConnectionSeeder); ok && target.GetConnection() != "" {
+		var err error
+		if seederDB, err = database.OpenConnection(target.GetConnection()); err != nil {
+			return err
+		}
+	}
+
+	if tx, ok := s.seeder.(TransactionalSeeder); ok && !tx.WithinTransaction() {
+		if err := s.seeder.Seed(seederDB); err != nil {
+			return err
+		}
+		return recordSeeder(db, s.name)
+	}
+
+	return seederDB.Transaction(func(tx *gorm.DB) error {
+		if err := s.seeder.Seed(tx); err != nil {
+			return err
+		}
+		if seederDB == db {
+			return recordSeeder(tx, s.name)
+		}
+		return recordSeeder(db, s.name)
+	})
+}
+
+// createSeedersTable creates the seeders table unless it exists
+func createSeedersTable(db *gorm.DB) error {
+	schema := database.NewSchemaOn(db)
+	if schema.HasTable("seeders") {
+		return nil
+	}
+
+	return schema.Create("seeders", func(table *database.Blueprint) {
+		table.ID()
+		table.String("name", 255).NotNullable()
+		table.Timestamp("ran_at").NotNullable()
+		table.UniqueIndex([]string{"name"})
+	})
+}
+
+func recordSeeder(db *gorm.DB, name string) error {
+	return db.Where(SeederRecord{Name: name}).
+		Assign(SeederRecord{RanAt: time.Now()}).
+		FirstOrCreate(&SeederRecord{}).Error
+}
+
+// NewDatabaseSeeder is NewDatabaseSeederE without the error, which Run returns
+// instead when a seeder is unknown or the dependencies form a cycle
+//
+// Deprecated: use NewDatabaseSeederE to handle the error where it happens
 func NewDatabaseSeeder(selectedCSV string) *DatabaseSeeder {
-	ds := &DatabaseSeeder{}
+	ds, err := NewDatabaseSeederE(selectedCSV)
+	if err != nil {
+		return &DatabaseSeeder{err: err}
+	}
+	return ds
+}
 
+// NewDatabaseSeederE selects the comma separated seeders, or every registered
+// seeder when selectedCSV is empty. Seeders run in name order, each one after
+// its dependencies
+func NewDatabaseSeederE(selectedCSV string) (*DatabaseSeeder, error) {
+	var names []string
 	if selectedCSV == "" {
-		for _, s := range SeederRegistry {
-			ds.AddSeeder(s)
+		for name := range SeederRegistry {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	} else {
+		for _, name := range strings.Split(selectedCSV, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				names = append(names, name)
+			}
 		}
-		return ds
 	}
 
-	for _, name := range strings.Split(selectedCSV, ",") {
-		seeder, ok := SeederRegistry[strings.ToLower(name)]
+	ds := &DatabaseSeeder{}
+	visited := map[string]bool{}
+	visiting := map[string]bool{}
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if visited[name] {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("seeder dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		seeder, ok := SeederRegistry[name]
 		if !ok {
-			fmt.Printf("Seeder file '%s.go' not found or not registered\n", name)
-			os.Exit(1)
+			if len(path) > 0 {
+				return fmt.Errorf("seeder %q required by %s is not registered", name, path[len(path)-1])
+			}
+			return fmt.Errorf("seeder file '%s.go' not found or not registered", name)
+		}
+
+		visiting[name] = true
+		if dependent, ok := seeder.(DependentSeeder); ok {
+			for _, dependency := range dependent.Dependencies() {
+				if err := visit(strings.ToLower(dependency), append(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+
+		ds.AddNamedSeeder(name, seeder)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
 		}
-		ds.AddSeeder(seeder)
 	}
 
-	return ds
+	return ds, nil
 }
 
 var SeederRegistry = map[string]Seeder{}
 
+// RegisterSeeder registers a seeder under a case-insensitive name
 func RegisterSeeder(name string, seeder Seeder) {
-	SeederRegistry[name] = seeder
+	SeederRegistry[strings.ToLower(name)] = seeder
 }
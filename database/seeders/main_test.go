@@ -0,0 +1,202 @@
+package seeders
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/galaplate/core/database"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type testRow struct {
+	ID   uint
+	Name string
+}
+
+type testSeeder struct {
+	name         string
+	dependencies []string
+	environments []string
+	err          error
+	runs         *int
+}
+
+func (s testSeeder) Seed(db *gorm.DB) error {
+	*s.runs++
+	if err := db.Create(&testRow{Name: s.name}).Error; err != nil {
+		return err
+	}
+	return s.err
+}
+
+func (s testSeeder) Dependencies() []string {
+	return s.dependencies
+}
+
+func (s testSeeder) Environments() []string {
+	return s.environments
+}
+
+type plainSeeder struct {
+	database.WithoutTransaction
+	runs *int
+}
+
+func (s plainSeeder) Seed(db *gorm.DB) error {
+	*s.runs++
+	return nil
+}
+
+// useRegistry swaps SeederRegistry for the duration of the test
+func useRegistry(t *testing.T, registry map[string]Seeder) {
+	previous := SeederRegistry
+	SeederRegistry = map[string]Seeder{}
+	for name, seeder := range registry {
+		RegisterSeeder(name, seeder)
+	}
+	t.Cleanup(func() { SeederRegistry = previous })
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "seed.sqlite")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&testRow{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	return db
+}
+
+func TestNewDatabaseSeederOrder(t *testing.T) {
+	runs := 0
+	useRegistry(t, map[string]Seeder{
+		"posts":    testSeeder{name: "posts", dependencies: []string{"Users"}, runs: &runs},
+		"comments": testSeeder{name: "comments", dependencies: []string{"posts"}, runs: &runs},
+		"users":    testSeeder{name: "users", runs: &runs},
+		"audit":    testSeeder{name: "audit", runs: &runs},
+	})
+
+	ds, err := NewDatabaseSeederE("")
+	if err != nil {
+		t.Fatalf("Failed to build seeder: %v", err)
+	}
+	if expected := []string{"audit", "users", "posts", "comments"}; !reflect.DeepEqual(ds.Names(), expected) {
+		t.Errorf("Expected order %v, got %v", expected, ds.Names())
+	}
+
+	ds, err = NewDatabaseSeederE("Comments")
+	if err != nil {
+		t.Fatalf("Failed to build seeder: %v", err)
+	}
+	if expected := []string{"users", "posts", "comments"}; !reflect.DeepEqual(ds.Names(), expected) {
+		t.Errorf("Expected dependencies to be included, got %v", ds.Names())
+	}
+
+	if _, err := NewDatabaseSeederE("missing"); err == nil {
+		t.Error("Expected an error for an unknown seeder")
+	}
+
+	// The deprecated constructor returns the error from Run instead of exiting
+	if err := NewDatabaseSeeder("missing").Run(newTestDB(t)); err == nil {
+		t.Error("Expected Run to return the unknown seeder")
+	}
+}
+
+func TestNewDatabaseSeederCycle(t *testing.T) {
+	runs := 0
+	useRegistry(t, map[string]Seeder{
+		"a": testSeeder{dependencies: []string{"b"}, runs: &runs},
+		"b": testSeeder{dependencies: []string{"a"}, runs: &runs},
+	})
+
+	_, err := NewDatabaseSeederE("")
+	if err == nil || !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Errorf("Expected a dependency cycle error, got %v", err)
+	}
+}
+
+func TestDatabaseSeederOnceAndEnvironments(t *testing.T) {
+	db := newTestDB(t)
+	users, demo, plain := 0, 0, 0
+	useRegistry(t, map[string]Seeder{
+		"users": testSeeder{name: "users", runs: &users},
+		"demo":  testSeeder{name: "demo", environments: []string{"local"}, runs: &demo},
+		"plain": plainSeeder{runs: &plain},
+	})
+
+	for range 2 {
+		ds, err := NewDatabaseSeederE("")
+		if err != nil {
+			t.Fatalf("Failed to build seeder: %v", err)
+		}
+		ds.Once = true
+		ds.Environment = "production"
+		if err := ds.Run(db); err != nil {
+			t.Fatalf("Seeding failed: %v", err)
+		}
+	}
+
+	if users != 1 || plain != 1 {
+		t.Errorf("Expected --once to run each seeder once, got users=%d plain=%d", users, plain)
+	}
+	if demo != 0 {
+		t.Errorf("Expected the local seeder to be skipped in production, ran %d times", demo)
+	}
+
+	var recorded []string
+	db.Model(&SeederRecord{}).Order("name").Pluck("name", &recorded)
+	if expected := []string{"plain", "users"}; !reflect.DeepEqual(recorded, expected) {
+		t.Errorf("Expected seeders table %v, got %v", expected, recorded)
+	}
+}
+
+func TestDatabaseSeederRollsBackFailedSeeder(t *testing.T) {
+	db := newTestDB(t)
+	runs := 0
+	useRegistry(t, map[string]Seeder{
+		"broken": testSeeder{name: "broken", err: errors.New("boom"), runs: &runs},
+	})
+
+	ds, err := NewDatabaseSeederE("broken")
+	if err != nil {
+		t.Fatalf("Failed to build seeder: %v", err)
+	}
+	if err := ds.Run(db); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("Expected the seeder error, got %v", err)
+	}
+
+	var rows, records int64
+	db.Model(&testRow{}).Count(&rows)
+	db.Model(&SeederRecord{}).Count(&records)
+	if rows != 0 || records != 0 {
+		t.Errorf("Expected the failed seeder to be rolled back, got %d rows and %d records", rows, records)
+	}
+}
+
+func TestAddSeederNamesAfterType(t *testing.T) {
+	db := newTestDB(t)
+	runs := 0
+
+	ds := &DatabaseSeeder{Once: true}
+	ds.AddSeeder(plainSeeder{runs: &runs})
+	if expected := []string{"plainseeder"}; !reflect.DeepEqual(ds.Names(), expected) {
+		t.Errorf("Expected %v, got %v", expected, ds.Names())
+	}
+
+	for range 2 {
+		if err := ds.Run(db); err != nil {
+			t.Fatalf("Seeding failed: %v", err)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("Expected the seeder to be recorded under its type name, ran %d times", runs)
+	}
+}
@@ -36,6 +36,11 @@ func (f *Factory) RegisterProvider(name string, provider filestorage.FileStorage
 	f.providers[name] = provider
 }
 
+// RemoveProvider unregisters a storage provider
+func (f *Factory) RemoveProvider(name string) {
+	delete(f.providers, name)
+}
+
 // GetProvider returns a storage provider by name
 func (f *Factory) GetProvider(name string) (filestorage.FileStorageProvider, error) {
 	if provider, exists := f.providers[name]; exists {
@@ -134,6 +139,16 @@ func (f *Factory) ValidateFileExistsWith(providerName string, metadata filestora
 	return provider.ValidateFileExists(metadata)
 }
 
+// Initialized reports whether the global factory was initialized
+func Initialized() bool {
+	return globalFactory != nil
+}
+
+// Reset discards the global factory, as if Initialize was never called
+func Reset() {
+	globalFactory = nil
+}
+
 // Global returns the global factory instance
 func Global() *Factory {
 	if globalFactory == nil {
@@ -157,6 +172,11 @@ func RegisterProvider(name string, provider filestorage.FileStorageProvider) {
 	Global().RegisterProvider(name, provider)
 }
 
+// RemoveProvider unregisters a provider from the global factory
+func RemoveProvider(name string) {
+	Global().RemoveProvider(name)
+}
+
 // Upload uploads a file using the default provider from global factory
 func Upload(file *multipart.FileHeader) filestorage.UploadMetadata {
 	return Global().Upload(file)
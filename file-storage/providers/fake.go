@@ -0,0 +1,177 @@
+package providers
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	filestorage "github.com/galaplate/core/file-storage"
+)
+
+// FakeFile is a file held by FakeStorage
+type FakeFile struct {
+	Metadata     filestorage.UploadMetadata
+	OriginalName string
+	Content      []byte
+}
+
+// FakeStorage is an in-memory storage provider for tests. It validates uploads
+// like LocalStorage but never touches the disk
+type FakeStorage struct {
+	mu     sync.Mutex
+	config filestorage.FileUploadConfig
+	files  map[string]FakeFile
+}
+
+func NewFakeStorage(config ...filestorage.FileUploadConfig) *FakeStorage {
+	cfg := filestorage.DefaultFileUploadConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	return &FakeStorage{
+		config: cfg,
+		files:  make(map[string]FakeFile),
+	}
+}
+
+func (fs *FakeStorage) Upload(file *multipart.FileHeader) filestorage.UploadMetadata {
+	if file.Size > fs.config.MaxSize {
+		return filestorage.UploadMetadata{
+			Error: "file_too_large",
+		}
+	}
+
+	contentType := file.Header.Get("Content-Type")
+	if !slices.Contains(fs.config.AllowedTypes, contentType) {
+		return filestorage.UploadMetadata{
+			Error: "invalid_file_type",
+		}
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return filestorage.UploadMetadata{
+			Error: "file_open_failed",
+		}
+	}
+	defer src.Close()
+
+	content, err := io.ReadAll(src)
+	if err != nil {
+		return filestorage.UploadMetadata{
+			Error: "file_save_failed",
+		}
+	}
+
+	ext := filepath.Ext(file.Filename)
+	fileName := fmt.Sprintf("%s_%s%s", time.Now().Format("20060102150405"), uuid.New().String(), ext)
+
+	metadata := filestorage.UploadMetadata{
+		FileName:    fileName,
+		FilePath:    filepath.Join(fs.config.UploadDir, fileName),
+		FileSize:    file.Size,
+		MimeType:    contentType,
+		StorageType: "fake",
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.files[metadata.FilePath] = FakeFile{
+		Metadata:     metadata,
+		OriginalName: file.Filename,
+		Content:      content,
+	}
+
+	return metadata
+}
+
+func (fs *FakeStorage) Delete(filePath string, storageType string) error {
+	if filePath == "" {
+		return fmt.Errorf("invalid_file_path")
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	delete(fs.files, filePath)
+	return nil
+}
+
+func (fs *FakeStorage) ValidateFileExists(metadata filestorage.UploadMetadata) bool {
+	_, ok := fs.Get(metadata.FilePath)
+	return ok
+}
+
+func (fs *FakeStorage) GetDownloadURL(metadata filestorage.UploadMetadata) string {
+	return metadata.FilePath
+}
+
+func (fs *FakeStorage) GetProviderName() string {
+	return "fake"
+}
+
+// Put stores content at path, to set up files a test expects to exist
+func (fs *FakeStorage) Put(path string, content []byte) filestorage.UploadMetadata {
+	metadata := filestorage.UploadMetadata{
+		FileName:    filepath.Base(path),
+		FilePath:    path,
+		FileSize:    int64(len(content)),
+		StorageType: "fake",
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.files[path] = FakeFile{
+		Metadata:     metadata,
+		OriginalName: filepath.Base(path),
+		Content:      content,
+	}
+	return metadata
+}
+
+// Get returns the file stored at path
+func (fs *FakeStorage) Get(path string) (FakeFile, bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	file, ok := fs.files[path]
+	return file, ok
+}
+
+// Find returns the file whose path, generated name or uploaded name is name
+func (fs *FakeStorage) Find(name string) (FakeFile, bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if file, ok := fs.files[name]; ok {
+		return file, true
+	}
+	for _, file := range fs.files {
+		if file.Metadata.FileName == name || file.OriginalName == name {
+			return file, true
+		}
+	}
+	return FakeFile{}, false
+}
+
+// Files returns the stored paths in order
+func (fs *FakeStorage) Files() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	paths := make([]string, 0, len(fs.files))
+	for path := range fs.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -194,7 +194,30 @@ type Job interface {
 	RetryAfter() time.Duration
 }
 
+// DispatchFunc receives dispatched jobs in place of the jobs table
+type DispatchFunc func(job Job, payload json.RawMessage) error
+
+var dispatcher DispatchFunc
+
+// SetDispatcher routes Dispatch to fn instead of the jobs table, which is how
+// testing.FakeQueue records jobs. The returned function restores the previous dispatcher
+func SetDispatcher(fn DispatchFunc) func() {
+	previous := dispatcher
+	dispatcher = fn
+	return func() {
+		dispatcher = previous
+	}
+}
+
 func Dispatch(job Job, params ...any) error {
+	if dispatcher != nil {
+		payload, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		return dispatcher(job, payload)
+	}
+
 	_, err := SaveJobToDB(JobEnqueueRequest{
 		Type:    job.Type(),
 		Payload: params,
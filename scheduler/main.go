@@ -16,9 +16,17 @@ type Scheduler struct {
 	started bool
 }
 
+// specParser parses task specs, with a leading seconds field as cron.WithSeconds
+var specParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ParseSpec parses a task spec the way the scheduler does
+func ParseSpec(spec string) (cron.Schedule, error) {
+	return specParser.Parse(spec)
+}
+
 func New() *Scheduler {
 	return &Scheduler{
-		cron:    cron.New(cron.WithSeconds()),
+		cron:    cron.New(cron.WithParser(specParser)),
 		started: false,
 	}
 }
@@ -0,0 +1,49 @@
+package supports
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Date is a calendar date read from and written to JSON as "2006-01-02", used by
+// request DTOs of date columns since time.Time only accepts RFC 3339
+//
+//	DueAt supports.Date `json:"due_at" validate:"required"`
+type Date struct {
+	time.Time
+}
+
+// UnmarshalJSON parses a "2006-01-02" string, leaving the date zero for null
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("date must be a string formatted as YYYY-MM-DD")
+	}
+
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+	d.Time = parsed
+
+	return nil
+}
+
+// MarshalJSON formats the date as "2006-01-02"
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(time.DateOnly))
+}
+
+// TimePtr returns the date as a *time.Time, nil for a nil Date
+func (d *Date) TimePtr() *time.Time {
+	if d == nil {
+		return nil
+	}
+	t := d.Time
+	return &t
+}
@@ -0,0 +1,10 @@
+package supports
+
+import "github.com/jinzhu/inflection"
+
+// Pluralize returns the English plural of a word with the inflection rules GORM
+// names tables with, so derived table names match the models
+// Example: Pluralize("category") -> "categories", Pluralize("person") -> "people"
+func Pluralize(word string) string {
+	return inflection.Plural(word)
+}
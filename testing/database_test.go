@@ -0,0 +1,157 @@
+package testing_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/galaplate/core/database"
+	coretesting "github.com/galaplate/core/testing"
+	"github.com/stretchr/testify/suite"
+)
+
+type createSuiteNotesTable struct {
+	database.BaseMigration
+}
+
+func (m *createSuiteNotesTable) Up(schema *database.Schema) error {
+	return schema.Create("suite_notes", func(table *database.Blueprint) {
+		table.ID()
+		table.String("title")
+	})
+}
+
+func (m *createSuiteNotesTable) Down(schema *database.Schema) error {
+	return schema.DropIfExists("suite_notes")
+}
+
+func init() {
+	database.Register(&createSuiteNotesTable{
+		BaseMigration: database.BaseMigration{Name: "create_suite_notes_table", Timestamp: 1},
+	})
+}
+
+// suiteConfigPath is the config directory of the database suites. Config is
+// loaded once per process, so the suites share it and its SQLite file
+var suiteConfigPath string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "galaplate-testing")
+	if err != nil {
+		panic(err)
+	}
+	files := map[string]string{
+		"app.yaml":      "key: testing\n",
+		"database.yaml": fmt.Sprintf("default: suite\nconnections:\n  suite:\n    driver: sqlite\n    database: %s\n", filepath.Join(dir, "suite.sqlite")),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			panic(err)
+		}
+	}
+	suiteConfigPath = dir
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// databaseSuiteConfig runs a suite on the SQLite file of suiteConfigPath
+func databaseSuiteConfig() *coretesting.TestConfig {
+	cfg := coretesting.DefaultTestConfig()
+	cfg.ConfigPath = suiteConfigPath
+	return cfg
+}
+
+type RefreshDatabaseSuite struct {
+	coretesting.RefreshDatabaseBeforeEachTest
+}
+
+func (s *RefreshDatabaseSuite) SetupSuite() {
+	s.Config = databaseSuiteConfig()
+}
+
+func (s *RefreshDatabaseSuite) TestFirstWritesNote() {
+	s.NoError(s.DB.Exec("INSERT INTO suite_notes (title) VALUES ('first')").Error)
+	coretesting.NewDatabaseHelper(&s.TestCase).AssertDatabaseCount("suite_notes", 1)
+}
+
+func (s *RefreshDatabaseSuite) TestSecondStartsEmpty() {
+	s.True(s.DB.Migrator().HasTable("suite_notes"))
+	coretesting.NewDatabaseHelper(&s.TestCase).AssertDatabaseCount("suite_notes", 0)
+}
+
+func TestRefreshDatabaseSuite(t *testing.T) {
+	suite.Run(t, new(RefreshDatabaseSuite))
+}
+
+type DatabaseTransactionsSuite struct {
+	coretesting.WithDatabaseTransactions
+}
+
+func (s *DatabaseTransactionsSuite) SetupSuite() {
+	s.Config = databaseSuiteConfig()
+	s.EnableRefreshDatabase()
+}
+
+func (s *DatabaseTransactionsSuite) TestFirstWritesNote() {
+	s.NoError(s.DB.Exec("INSERT INTO suite_notes (title) VALUES ('first')").Error)
+	coretesting.NewDatabaseHelper(&s.TestCase).AssertDatabaseCount("suite_notes", 1)
+}
+
+func (s *DatabaseTransactionsSuite) TestResetTransaction() {
+	s.NoError(s.DB.Exec("INSERT INTO suite_notes (title) VALUES ('reset')").Error)
+	s.NoError(s.ResetTransaction())
+	coretesting.NewDatabaseHelper(&s.TestCase).AssertDatabaseCount("suite_notes", 0)
+
+	// The transaction stays usable after the reset
+	s.NoError(s.DB.Exec("INSERT INTO suite_notes (title) VALUES ('after reset')").Error)
+	coretesting.NewDatabaseHelper(&s.TestCase).AssertDatabaseCount("suite_notes", 1)
+}
+
+func (s *DatabaseTransactionsSuite) TestSecondStartsEmpty() {
+	s.True(s.DB.Migrator().HasTable("suite_notes"))
+	coretesting.NewDatabaseHelper(&s.TestCase).AssertDatabaseCount("suite_notes", 0)
+}
+
+func TestDatabaseTransactionsSuite(t *testing.T) {
+	suite.Run(t, new(DatabaseTransactionsSuite))
+}
+
+type InMemoryDatabaseSuite struct {
+	coretesting.TestCase
+}
+
+func (s *InMemoryDatabaseSuite) SetupSuite() {
+	s.Config = databaseSuiteConfig()
+	s.Config.InMemoryDatabase = true
+}
+
+func (s *InMemoryDatabaseSuite) TestFirstWritesNote() {
+	s.NoError(s.DB.Exec("INSERT INTO suite_notes (title) VALUES ('first')").Error)
+	coretesting.NewDatabaseHelper(&s.TestCase).AssertDatabaseCount("suite_notes", 1)
+}
+
+func (s *InMemoryDatabaseSuite) TestSecondStartsFromSnapshot() {
+	s.True(s.DB.Migrator().HasTable("suite_notes"))
+	s.False(s.DB.Migrator().HasTable("migrations_lock"), "Expected the migration lock to be left out of the snapshot")
+	coretesting.NewDatabaseHelper(&s.TestCase).AssertDatabaseCount("suite_notes", 0)
+	coretesting.NewDatabaseHelper(&s.TestCase).AssertDatabaseCount("migrations", 1)
+}
+
+func TestInMemoryDatabaseSuite(t *testing.T) {
+	suite.Run(t, new(InMemoryDatabaseSuite))
+}
+
+func TestRefreshDatabaseRequiresMigrations(t *testing.T) {
+	registry := database.DefaultRegistry
+	database.DefaultRegistry = &database.MigrationRegistry{}
+	defer func() { database.DefaultRegistry = registry }()
+
+	err := coretesting.NewTestCase().RefreshDatabase()
+	if err == nil || !strings.Contains(err.Error(), "no migrations are registered") {
+		t.Errorf("Expected an error without registered migrations, got: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package testing_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	filestorage "github.com/galaplate/core/file-storage"
+	"github.com/galaplate/core/file-storage/factory"
+	"github.com/galaplate/core/file-storage/providers"
+	"github.com/galaplate/core/queue"
+	"github.com/galaplate/core/scheduler"
+	coretesting "github.com/galaplate/core/testing"
+)
+
+type welcomeEmailJob struct {
+	handled *[]string
+}
+
+func (welcomeEmailJob) Type() string              { return "welcome_email" }
+func (welcomeEmailJob) MaxAttempts() int          { return 1 }
+func (welcomeEmailJob) RetryAfter() time.Duration { return time.Minute }
+func (j welcomeEmailJob) Handle(payload json.RawMessage) error {
+	*j.handled = append(*j.handled, string(payload))
+	return nil
+}
+
+func TestFakeQueue(t *testing.T) {
+	jobs := coretesting.FakeQueue()
+	defer jobs.Restore()
+
+	var handled []string
+	job := welcomeEmailJob{handled: &handled}
+
+	if err := queue.Dispatch(job, "alice@example.com"); err != nil {
+		t.Fatalf("Failed to dispatch: %v", err)
+	}
+	queue.Dispatch(job, "bob@example.com")
+
+	jobs.AssertDispatched(t, "welcome_email", func(payload json.RawMessage) bool {
+		var params []string
+		json.Unmarshal(payload, &params)
+		return params[0] == "alice@example.com"
+	})
+	jobs.AssertDispatchedTimes(t, "welcome_email", 2)
+	jobs.AssertNotDispatched(t, "invoice", nil)
+
+	if err := jobs.RunDispatched(); err != nil {
+		t.Fatalf("Failed to run jobs: %v", err)
+	}
+	if len(handled) != 2 || handled[0] != `["alice@example.com"]` {
+		t.Errorf("Expected both jobs handled in order, got %v", handled)
+	}
+}
+
+type countingTask struct {
+	spec string
+	runs *int
+}
+
+func (c countingTask) Handle() (string, func()) {
+	return c.spec, func() { *c.runs++ }
+}
+
+func TestFakeScheduler(t *testing.T) {
+	var hourly, daily int
+	scheduler.RegisterScheduler("test_hourly", countingTask{spec: "0 0 * * * *", runs: &hourly})
+	scheduler.RegisterScheduler("test_daily", countingTask{spec: "@daily", runs: &daily})
+	defer delete(scheduler.SchedulerRegistry, "test_hourly")
+	defer delete(scheduler.SchedulerRegistry, "test_daily")
+
+	clock := coretesting.FakeScheduler(time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC))
+
+	clock.Advance(20 * time.Minute)
+	clock.AssertNotRan(t, "test_hourly")
+
+	clock.Advance(3 * time.Hour)
+	clock.AssertRanTimes(t, "test_hourly", 3)
+	clock.AssertNotRan(t, "test_daily")
+
+	clock.Advance(24 * time.Hour)
+	clock.AssertRan(t, "test_daily")
+	if hourly != 27 || daily != 1 {
+		t.Errorf("Expected 27 hourly and 1 daily runs, got %d and %d", hourly, daily)
+	}
+}
+
+func TestFakeStorage(t *testing.T) {
+	storage := coretesting.FakeStorage("test_fake")
+	defer storage.Restore()
+
+	metadata := factory.UploadWith("test_fake", coretesting.ImageFileHeader("avatar.png", 2, 2))
+	if metadata.Error != "" {
+		t.Fatalf("Failed to upload: %s", metadata.Error)
+	}
+
+	storage.AssertStored(t, "avatar.png")
+	storage.AssertStored(t, metadata.FilePath)
+	storage.AssertCount(t, 1)
+
+	rejected := factory.UploadWith("test_fake", coretesting.FileHeader("notes.txt", "text/plain", []byte("hello")))
+	if rejected.Error != "invalid_file_type" {
+		t.Errorf("Expected the fake to validate file types, got %q", rejected.Error)
+	}
+
+	factory.DeleteWith("test_fake", metadata.FilePath, metadata.StorageType)
+	storage.AssertMissing(t, "avatar.png")
+	storage.AssertCount(t, 0)
+}
+
+func TestFakeStorageRestore(t *testing.T) {
+	factory.Reset()
+	defer factory.Reset()
+
+	coretesting.FakeStorage("test_initialized").Restore()
+	if factory.Initialized() {
+		t.Error("Expected Restore to discard the factory the fake initialized")
+	}
+
+	local := providers.NewFakeStorage()
+	factory.Initialize("local", map[string]filestorage.FileStorageProvider{"local": local})
+
+	coretesting.FakeStorage("test_added").Restore()
+	if _, err := factory.Global().GetProvider("test_added"); err == nil {
+		t.Error("Expected Restore to remove the provider the fake added")
+	}
+
+	fake := coretesting.FakeStorage("local")
+	if provider, _ := factory.Global().GetProvider("local"); provider != fake.FakeStorage {
+		t.Fatal("Expected the fake to replace the local provider")
+	}
+	fake.Restore()
+	if provider, _ := factory.Global().GetProvider("local"); provider != local {
+		t.Error("Expected Restore to register the replaced provider again")
+	}
+}
@@ -22,3 +22,12 @@ func (r *RefreshDatabaseBeforeEachTest) SetupTest() {
 	r.RefreshDatabaseBetweenTests()
 	r.TestCase.SetupTest()
 }
+
+type WithDatabaseTransactions struct {
+	TestCase
+}
+
+func (w *WithDatabaseTransactions) SetupTest() {
+	w.EnableDatabaseTransactions()
+	w.TestCase.SetupTest()
+}
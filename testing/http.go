@@ -0,0 +1,360 @@
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galaplate/core/supports"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestResponse is a response of the test application with chainable assertions
+type TestResponse struct {
+	tc       *TestCase
+	Response *http.Response
+	Body     []byte
+}
+
+// WithHeader sets a header sent with every following request of the test
+func (tc *TestCase) WithHeader(name, value string) *TestCase {
+	if tc.headers == nil {
+		tc.headers = map[string]string{}
+	}
+	tc.headers[name] = value
+	return tc
+}
+
+// WithToken sends a bearer token with every following request of the test
+func (tc *TestCase) WithToken(token string) *TestCase {
+	return tc.WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithCookie sends a cookie with every following request of the test. Cookies set
+// by responses are carried over the same way, which keeps sessions alive
+func (tc *TestCase) WithCookie(name, value string) *TestCase {
+	if tc.cookies == nil {
+		tc.cookies = map[string]string{}
+	}
+	tc.cookies[name] = value
+	return tc
+}
+
+// ActingAs makes user the c.Locals("user") of the following requests, as set by
+// an auth middleware and read by policies
+func (tc *TestCase) ActingAs(user any) *TestCase {
+	tc.actingAs = user
+	return tc
+}
+
+// actingAsMiddleware sets the ActingAs user on each request
+func (tc *TestCase) actingAsMiddleware(c *fiber.Ctx) error {
+	if tc.actingAs != nil {
+		c.Locals("user", tc.actingAs)
+	}
+	return c.Next()
+}
+
+// resetHTTPState forgets the headers, cookies and user of the previous test
+func (tc *TestCase) resetHTTPState() {
+	tc.headers = nil
+	tc.cookies = nil
+	tc.actingAs = nil
+}
+
+func (tc *TestCase) Get(path string) *TestResponse {
+	return tc.Call(fiber.MethodGet, path, nil, "")
+}
+
+func (tc *TestCase) PostJSON(path string, body any) *TestResponse {
+	return tc.callJSON(fiber.MethodPost, path, body)
+}
+
+func (tc *TestCase) PutJSON(path string, body any) *TestResponse {
+	return tc.callJSON(fiber.MethodPut, path, body)
+}
+
+func (tc *TestCase) PatchJSON(path string, body any) *TestResponse {
+	return tc.callJSON(fiber.MethodPatch, path, body)
+}
+
+func (tc *TestCase) DeleteJSON(path string, body any) *TestResponse {
+	return tc.callJSON(fiber.MethodDelete, path, body)
+}
+
+// PostMultipart posts a multipart form with fields and files keyed by form field,
+// files are built with FileHeader or ImageFileHeader
+func (tc *TestCase) PostMultipart(path string, fields map[string]string, files map[string]*multipart.FileHeader) *TestResponse {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	for field, file := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, field, file.Filename))
+		header.Set("Content-Type", file.Header.Get("Content-Type"))
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			log.Panicf("Failed to add file %s: %v", file.Filename, err)
+		}
+		src, err := file.Open()
+		if err != nil {
+			log.Panicf("Failed to open file %s: %v", file.Filename, err)
+		}
+		io.Copy(part, src)
+		src.Close()
+	}
+	writer.Close()
+
+	return tc.Call(fiber.MethodPost, path, &body, writer.FormDataContentType())
+}
+
+func (tc *TestCase) callJSON(method, path string, body any) *TestResponse {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			log.Panicf("Failed to encode request body: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	return tc.Call(method, path, reader, fiber.MIMEApplicationJSON)
+}
+
+// Call sends a request to the test application with the test headers and cookies,
+// and keeps the cookies the response sets
+func (tc *TestCase) Call(method, path string, body io.Reader, contentType string) *TestResponse {
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Accept", fiber.MIMEApplicationJSON)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for name, value := range tc.headers {
+		req.Header.Set(name, value)
+	}
+	for name, value := range tc.cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+
+	resp, err := tc.App.Test(req, -1)
+	tc.Require().NoError(err, "Request %s %s failed", method, path)
+
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	tc.Require().NoError(err, "Failed to read response of %s %s", method, path)
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.MaxAge < 0 || (!cookie.Expires.IsZero() && cookie.Expires.Before(time.Now())) {
+			delete(tc.cookies, cookie.Name)
+			continue
+		}
+		tc.WithCookie(cookie.Name, cookie.Value)
+	}
+
+	return &TestResponse{tc: tc, Response: resp, Body: content}
+}
+
+// JSON decodes the response body into v
+func (r *TestResponse) JSON(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+func (r *TestResponse) AssertStatus(status int) *TestResponse {
+	assert.Equal(r.tc.T(), status, r.Response.StatusCode, "Expected status %d, got %d: %s", status, r.Response.StatusCode, r.Body)
+	return r
+}
+
+func (r *TestResponse) AssertOK() *TestResponse {
+	return r.AssertStatus(fiber.StatusOK)
+}
+
+func (r *TestResponse) AssertCreated() *TestResponse {
+	return r.AssertStatus(fiber.StatusCreated)
+}
+
+func (r *TestResponse) AssertHeader(name, value string) *TestResponse {
+	assert.Equal(r.tc.T(), value, r.Response.Header.Get(name), "Unexpected %s header", name)
+	return r
+}
+
+// AssertCookie asserts the response sets a cookie with the given value
+func (r *TestResponse) AssertCookie(name, value string) *TestResponse {
+	for _, cookie := range r.Response.Cookies() {
+		if cookie.Name == name {
+			assert.Equal(r.tc.T(), value, cookie.Value, "Unexpected value for cookie %s", name)
+			return r
+		}
+	}
+	assert.Fail(r.tc.T(), "Cookie is not set", "Expected the response to set cookie %s", name)
+	return r
+}
+
+func (r *TestResponse) AssertBodyContains(substring string) *TestResponse {
+	assert.Contains(r.tc.T(), string(r.Body), substring)
+	return r
+}
+
+// AssertJSONPath asserts the value at a dot separated path, array elements are
+// addressed by index as in "data.users.0.email"
+func (r *TestResponse) AssertJSONPath(path string, expected any) *TestResponse {
+	actual, ok := r.jsonPath(path)
+	if !assert.True(r.tc.T(), ok, "Expected JSON path %s to exist in %s", path, r.Body) {
+		return r
+	}
+
+	assert.Equal(r.tc.T(), normalizeJSON(expected), actual, "Unexpected value at JSON path %s", path)
+	return r
+}
+
+// AssertJSONMissingPath asserts nothing exists at a dot separated path
+func (r *TestResponse) AssertJSONMissingPath(path string) *TestResponse {
+	_, ok := r.jsonPath(path)
+	assert.False(r.tc.T(), ok, "Expected JSON path %s not to exist", path)
+	return r
+}
+
+// AssertJSONStructure asserts the response has the given keys. Nested objects are
+// described with maps, and the "*" key applies a structure to every array element
+//
+//	res.AssertJSONStructure(map[string]any{
+//		"data": map[string]any{"*": []string{"id", "email"}},
+//		"meta": []string{"total"},
+//	})
+func (r *TestResponse) AssertJSONStructure(structure any) *TestResponse {
+	var data any
+	if !assert.NoError(r.tc.T(), r.JSON(&data), "Response is not JSON: %s", r.Body) {
+		return r
+	}
+
+	r.assertStructure(structure, data, "")
+	return r
+}
+
+func (r *TestResponse) assertStructure(structure any, data any, prefix string) {
+	switch s := structure.(type) {
+	case []string:
+		for _, key := range s {
+			r.assertStructure(map[string]any{key: nil}, data, prefix)
+		}
+	case map[string]any:
+		for key, nested := range s {
+			if key == "*" {
+				items, ok := data.([]any)
+				if !assert.True(r.tc.T(), ok, "Expected %s to be an array", strings.TrimSuffix(prefix, ".")) {
+					continue
+				}
+				for i, item := range items {
+					r.assertStructure(nested, item, prefix+strconv.Itoa(i)+".")
+				}
+				continue
+			}
+
+			object, ok := data.(map[string]any)
+			value, exists := object[key]
+			if !assert.True(r.tc.T(), ok && exists, "Expected JSON path %s%s to exist", prefix, key) {
+				continue
+			}
+			if nested != nil {
+				r.assertStructure(nested, value, prefix+key+".")
+			}
+		}
+	}
+}
+
+// AssertValidationErrors asserts a 422 response with errors for every field,
+// as returned by the validator in GlobalErrorHandlerResp.Errors
+func (r *TestResponse) AssertValidationErrors(fields ...string) *TestResponse {
+	errors := r.validationErrors()
+	for _, field := range fields {
+		assert.Contains(r.tc.T(), errors, field, "Expected a validation error for %s", field)
+	}
+	return r
+}
+
+// AssertValidationError asserts the validation error message of a field
+func (r *TestResponse) AssertValidationError(field, message string) *TestResponse {
+	errors := r.validationErrors()
+	if assert.Contains(r.tc.T(), errors, field, "Expected a validation error for %s", field) {
+		assert.Equal(r.tc.T(), message, errors[field], "Unexpected validation error for %s", field)
+	}
+	return r
+}
+
+// AssertNoValidationErrors asserts the response holds no validation error for the
+// fields, or none at all when no field is given
+func (r *TestResponse) AssertNoValidationErrors(fields ...string) *TestResponse {
+	var resp supports.GlobalErrorHandlerResp
+	r.JSON(&resp)
+
+	if len(fields) == 0 {
+		assert.Empty(r.tc.T(), resp.Errors, "Expected no validation errors")
+	}
+	for _, field := range fields {
+		assert.NotContains(r.tc.T(), resp.Errors, field, "Expected no validation error for %s", field)
+	}
+	return r
+}
+
+func (r *TestResponse) validationErrors() map[string]string {
+	r.AssertStatus(fiber.StatusUnprocessableEntity)
+
+	var resp supports.GlobalErrorHandlerResp
+	assert.NoError(r.tc.T(), r.JSON(&resp), "Response is not JSON: %s", r.Body)
+	return resp.Errors
+}
+
+// jsonPath returns the value at a dot separated path of the JSON body
+func (r *TestResponse) jsonPath(path string) (any, bool) {
+	var current any
+	if err := r.JSON(&current); err != nil {
+		return nil, false
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// normalizeJSON converts a Go value to its decoded JSON form, so 1 equals float64(1)
+func normalizeJSON(value any) any {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var normalized any
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
@@ -0,0 +1,93 @@
+package testing_test
+
+import (
+	"mime/multipart"
+	"testing"
+
+	"github.com/galaplate/core/supports"
+	coretesting "github.com/galaplate/core/testing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+)
+
+type HTTPTestSuite struct {
+	coretesting.TestCase
+}
+
+func (s *HTTPTestSuite) SetupSuite() {
+	s.Config = coretesting.DefaultTestConfig()
+	s.Config.EnvFile = ""
+	s.Config.ConfigOverrides = map[string]any{"app.key": "test-secret"}
+	s.Config.InMemoryDatabase = true
+	s.Config.SetupRoutes = func(app *fiber.App) {
+		app.Post("/users", func(c *fiber.Ctx) error {
+			var body map[string]string
+			c.BodyParser(&body)
+			if body["email"] == "" {
+				return &supports.GlobalErrorHandlerResp{
+					Status:  fiber.StatusUnprocessableEntity,
+					Message: "Validation failed",
+					Errors:  map[string]string{"email": "email is required"},
+				}
+			}
+			return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+				"data": fiber.Map{"user": fiber.Map{"id": 1, "email": body["email"]}},
+			})
+		})
+		app.Post("/login", func(c *fiber.Ctx) error {
+			c.Cookie(&fiber.Cookie{Name: "session_id", Value: "abc"})
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+		app.Get("/me", func(c *fiber.Ctx) error {
+			return c.JSON(fiber.Map{"user": c.Locals("user"), "session": c.Cookies("session_id")})
+		})
+		app.Post("/avatar", func(c *fiber.Ctx) error {
+			file, err := c.FormFile("avatar")
+			if err != nil {
+				return err
+			}
+			return c.JSON(fiber.Map{"name": file.Filename, "caption": c.FormValue("caption")})
+		})
+	}
+}
+
+func (s *HTTPTestSuite) TestJSONAssertions() {
+	s.PostJSON("/users", map[string]string{"email": "x@y.z"}).
+		AssertCreated().
+		AssertJSONPath("data.user.email", "x@y.z").
+		AssertJSONPath("data.user.id", 1).
+		AssertJSONMissingPath("data.user.password").
+		AssertJSONStructure(map[string]any{
+			"data": map[string]any{"user": []string{"id", "email"}},
+		})
+}
+
+func (s *HTTPTestSuite) TestValidationErrors() {
+	s.PostJSON("/users", map[string]string{}).
+		AssertValidationErrors("email").
+		AssertValidationError("email", "email is required")
+}
+
+func (s *HTTPTestSuite) TestCookiesAndActingAs() {
+	s.PostJSON("/login", nil).AssertCookie("session_id", "abc")
+
+	s.ActingAs("alice").
+		Get("/me").
+		AssertOK().
+		AssertJSONPath("user", "alice").
+		AssertJSONPath("session", "abc")
+}
+
+func (s *HTTPTestSuite) TestMultipartUpload() {
+	s.PostMultipart("/avatar",
+		map[string]string{"caption": "me"},
+		map[string]*multipart.FileHeader{"avatar": coretesting.ImageFileHeader("me.png", 1, 1)},
+	).
+		AssertOK().
+		AssertJSONPath("name", "me.png").
+		AssertJSONPath("caption", "me")
+}
+
+func TestHTTPTestSuite(t *testing.T) {
+	suite.Run(t, new(HTTPTestSuite))
+}
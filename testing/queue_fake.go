@@ -0,0 +1,111 @@
+package testing
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/galaplate/core/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+// DispatchedJob is a job recorded by the queue fake
+type DispatchedJob struct {
+	Job     queue.Job
+	Payload json.RawMessage
+}
+
+// QueueFake records dispatched jobs instead of storing them in the jobs table
+type QueueFake struct {
+	mu         sync.Mutex
+	dispatched []DispatchedJob
+	pending    []DispatchedJob
+	restore    func()
+}
+
+// FakeQueue intercepts queue.Dispatch until Restore is called
+//
+//	jobs := coretesting.FakeQueue()
+//	defer jobs.Restore()
+//	jobs.AssertDispatched(s.T(), "send_welcome_email", nil)
+func FakeQueue() *QueueFake {
+	fake := &QueueFake{}
+	fake.restore = queue.SetDispatcher(fake.record)
+	return fake
+}
+
+func (f *QueueFake) record(job queue.Job, payload json.RawMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	dispatched := DispatchedJob{Job: job, Payload: payload}
+	f.dispatched = append(f.dispatched, dispatched)
+	f.pending = append(f.pending, dispatched)
+	return nil
+}
+
+// Restore sends dispatched jobs to the jobs table again
+func (f *QueueFake) Restore() {
+	f.restore()
+}
+
+// Dispatched returns the jobs of a type matching the payload filter, nil matching all
+func (f *QueueFake) Dispatched(jobType string, match func(payload json.RawMessage) bool) []DispatchedJob {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var jobs []DispatchedJob
+	for _, dispatched := range f.dispatched {
+		if dispatched.Job.Type() == jobType && (match == nil || match(dispatched.Payload)) {
+			jobs = append(jobs, dispatched)
+		}
+	}
+	return jobs
+}
+
+// AssertDispatched asserts a job of the type was dispatched with a matching payload
+func (f *QueueFake) AssertDispatched(t assert.TestingT, jobType string, match func(payload json.RawMessage) bool) bool {
+	if len(f.Dispatched(jobType, match)) == 0 {
+		return assert.Fail(t, "Job was not dispatched", "Expected a %s job to be dispatched", jobType)
+	}
+	return true
+}
+
+// AssertNotDispatched asserts no job of the type was dispatched with a matching payload
+func (f *QueueFake) AssertNotDispatched(t assert.TestingT, jobType string, match func(payload json.RawMessage) bool) bool {
+	if jobs := f.Dispatched(jobType, match); len(jobs) > 0 {
+		return assert.Fail(t, "Job was dispatched", "Expected no %s job to be dispatched, got %d", jobType, len(jobs))
+	}
+	return true
+}
+
+// AssertDispatchedTimes asserts how many jobs of the type were dispatched
+func (f *QueueFake) AssertDispatchedTimes(t assert.TestingT, jobType string, times int) bool {
+	return assert.Len(t, f.Dispatched(jobType, nil), times, "Unexpected number of %s jobs dispatched", jobType)
+}
+
+// AssertNothingDispatched asserts no job was dispatched at all
+func (f *QueueFake) AssertNothingDispatched(t assert.TestingT) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return assert.Empty(t, f.dispatched, "Expected no job to be dispatched")
+}
+
+// RunDispatched handles the dispatched jobs synchronously in dispatch order,
+// including jobs they dispatch themselves, and returns the first error
+func (f *QueueFake) RunDispatched() error {
+	for {
+		f.mu.Lock()
+		if len(f.pending) == 0 {
+			f.mu.Unlock()
+			return nil
+		}
+		next := f.pending[0]
+		f.pending = f.pending[1:]
+		f.mu.Unlock()
+
+		if err := next.Job.Handle(next.Payload); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package testing
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/galaplate/core/scheduler"
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTask is a registered scheduler task driven by the fake clock
+type fakeTask struct {
+	name     string
+	schedule cron.Schedule
+	run      func()
+	next     time.Time
+}
+
+// SchedulerFake runs the registered scheduler tasks on a fake clock
+type SchedulerFake struct {
+	mu    sync.Mutex
+	now   time.Time
+	tasks []*fakeTask
+	ran   map[string]int
+}
+
+// FakeScheduler creates a fake clock at start for the tasks registered with
+// scheduler.RegisterScheduler. Tasks only run when the clock is advanced
+//
+//	clock := coretesting.FakeScheduler(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+//	clock.Advance(24 * time.Hour)
+//	clock.AssertRanTimes(s.T(), "cleanup", 1)
+func FakeScheduler(start time.Time) *SchedulerFake {
+	fake := &SchedulerFake{
+		now: start,
+		ran: map[string]int{},
+	}
+
+	for name, handler := range scheduler.SchedulerRegistry {
+		spec, run := handler.Handle()
+		schedule, err := scheduler.ParseSpec(spec)
+		if err != nil {
+			log.Panicf("Invalid spec %q for scheduler %s: %v", spec, name, err)
+		}
+		fake.tasks = append(fake.tasks, &fakeTask{
+			name:     name,
+			schedule: schedule,
+			run:      run,
+			next:     schedule.Next(start),
+		})
+	}
+
+	// Tasks due at the same time run in name order
+	sort.Slice(fake.tasks, func(i, j int) bool {
+		return fake.tasks[i].name < fake.tasks[j].name
+	})
+
+	return fake
+}
+
+// Now returns the current fake time
+func (f *SchedulerFake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Advance moves the clock forward, running every task due on the way in time order
+func (f *SchedulerFake) Advance(d time.Duration) {
+	f.mu.Lock()
+	target := f.now.Add(d)
+	f.mu.Unlock()
+
+	for {
+		f.mu.Lock()
+		var due time.Time
+		for _, task := range f.tasks {
+			if !task.next.IsZero() && !task.next.After(target) && (due.IsZero() || task.next.Before(due)) {
+				due = task.next
+			}
+		}
+		if due.IsZero() {
+			f.now = target
+			f.mu.Unlock()
+			return
+		}
+
+		f.now = due
+		var runs []func()
+		for _, task := range f.tasks {
+			if task.next.Equal(due) {
+				f.ran[task.name]++
+				task.next = task.schedule.Next(due)
+				runs = append(runs, task.run)
+			}
+		}
+		f.mu.Unlock()
+
+		for _, run := range runs {
+			run()
+		}
+	}
+}
+
+// Ran returns how many times a task ran
+func (f *SchedulerFake) Ran(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.ran[name]
+}
+
+// AssertRan asserts a task ran at least once
+func (f *SchedulerFake) AssertRan(t assert.TestingT, name string) bool {
+	if f.Ran(name) == 0 {
+		return assert.Fail(t, "Task did not run", "Expected scheduler %s to run", name)
+	}
+	return true
+}
+
+// AssertNotRan asserts a task never ran
+func (f *SchedulerFake) AssertNotRan(t assert.TestingT, name string) bool {
+	return assert.Zero(t, f.Ran(name), "Expected scheduler %s not to run", name)
+}
+
+// AssertRanTimes asserts how many times a task ran
+func (f *SchedulerFake) AssertRanTimes(t assert.TestingT, name string, times int) bool {
+	return assert.Equal(t, times, f.Ran(name), "Unexpected number of runs for scheduler %s", name)
+}
@@ -0,0 +1,110 @@
+package testing
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"mime/multipart"
+	"net/textproto"
+
+	filestorage "github.com/galaplate/core/file-storage"
+	"github.com/galaplate/core/file-storage/factory"
+	"github.com/galaplate/core/file-storage/providers"
+	"github.com/stretchr/testify/assert"
+)
+
+// StorageFake is an in-memory provider swapped into the global file storage factory
+type StorageFake struct {
+	*providers.FakeStorage
+	restore func()
+}
+
+// FakeStorage replaces the provider registered under name with an in-memory one,
+// initializing the global factory with it as the default when needed. Restore
+// undoes exactly that: it discards a factory the fake initialized, removes a
+// provider it added and registers a provider it replaced again
+//
+//	storage := coretesting.FakeStorage("local")
+//	defer storage.Restore()
+//	storage.AssertStored(s.T(), "avatar.png")
+func FakeStorage(name string) *StorageFake {
+	fake := &StorageFake{FakeStorage: providers.NewFakeStorage()}
+
+	if !factory.Initialized() {
+		factory.Initialize(name, map[string]filestorage.FileStorageProvider{name: fake.FakeStorage})
+		fake.restore = factory.Reset
+		return fake
+	}
+
+	if previous, err := factory.Global().GetProvider(name); err == nil {
+		fake.restore = func() { factory.RegisterProvider(name, previous) }
+	} else {
+		fake.restore = func() { factory.RemoveProvider(name) }
+	}
+	factory.RegisterProvider(name, fake.FakeStorage)
+
+	return fake
+}
+
+// Restore undoes what FakeStorage changed in the global factory
+func (f *StorageFake) Restore() {
+	f.restore()
+}
+
+// AssertStored asserts a file is stored under a path, generated name or uploaded name
+func (f *StorageFake) AssertStored(t assert.TestingT, path string) bool {
+	if _, ok := f.Find(path); !ok {
+		return assert.Fail(t, "File is not stored", "Expected %s to be stored, got %v", path, f.Files())
+	}
+	return true
+}
+
+// AssertMissing asserts no file is stored under a path, generated name or uploaded name
+func (f *StorageFake) AssertMissing(t assert.TestingT, path string) bool {
+	if _, ok := f.Find(path); ok {
+		return assert.Fail(t, "File is stored", "Expected %s not to be stored", path)
+	}
+	return true
+}
+
+// AssertCount asserts how many files are stored
+func (f *StorageFake) AssertCount(t assert.TestingT, count int) bool {
+	return assert.Len(t, f.Files(), count, "Unexpected number of stored files")
+}
+
+// FileHeader builds an uploaded file from bytes, as upload handlers receive it
+// from c.FormFile
+func FileHeader(filename, contentType string, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filename))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		log.Panicf("Failed to build file header: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(int64(len(content)) + 1024)
+	if err != nil {
+		log.Panicf("Failed to build file header: %v", err)
+	}
+
+	return form.File["file"][0]
+}
+
+// ImageFileHeader builds an uploaded PNG image of the given size
+func ImageFileHeader(filename string, width, height int) *multipart.FileHeader {
+	var content bytes.Buffer
+	if err := png.Encode(&content, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		log.Panicf("Failed to encode image: %v", err)
+	}
+
+	return FileHeader(filename, "image/png", content.Bytes())
+}
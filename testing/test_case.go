@@ -1,10 +1,13 @@
 package testing
 
 import (
+	"bytes"
+	"errors"
 	"log"
+	"maps"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sync"
 
 	"github.com/galaplate/core/bootstrap"
 	"github.com/galaplate/core/config"
@@ -17,24 +20,52 @@ import (
 )
 
 type TestConfig struct {
-	EnvFile           string
-	SetupRoutes       func(*fiber.App)
-	ConfigPath        string
-	RefreshDatabase   bool
+	EnvFile         string
+	SetupRoutes     func(*fiber.App)
+	ConfigPath      string
+	RefreshDatabase bool
+	// DatabaseTransactions runs each test in a transaction rolled back in TearDownTest
+	DatabaseTransactions bool
+	// InMemoryDatabase runs the tests on an in-memory SQLite database, migrated
+	// once per package and restored from a snapshot before each test
+	InMemoryDatabase bool
+	// ConfigOverrides sets dotted config keys after the config files are loaded.
+	// Config files are loaded once per process, so suites sharing a package
+	// should configure their differences here
+	ConfigOverrides   map[string]any
 	ProjectRootOffset int
 	CustomBootstrap   func(*TestCase)
 	GormConfig        *gorm.Config
 	FiberConfig       *fiber.Config
 }
 
+const (
+	// testSavePoint is the savepoint each test transaction rolls back to
+	testSavePoint = "galaplate_test"
+
+	// memoryConnection is the connection used by InMemoryDatabase
+	memoryConnection = "testing_memory"
+)
+
+var (
+	// memorySnapshot holds the migrated in-memory database, shared by the package tests
+	memorySnapshot   []byte
+	memorySnapshotMu sync.Mutex
+)
+
 type TestCase struct {
 	suite.Suite
-	App               *fiber.App
-	DB                *gorm.DB
-	Config            *TestConfig
-	refreshDatabase   bool
-	databaseRefreshed bool
-	projectRoot       string
+	App                  *fiber.App
+	DB                   *gorm.DB
+	Config               *TestConfig
+	refreshDatabase      bool
+	databaseRefreshed    bool
+	databaseTransactions bool
+	connection           *gorm.DB
+	projectRoot          string
+	headers              map[string]string
+	cookies              map[string]string
+	actingAs             any
 }
 
 func DefaultTestConfig() *TestConfig {
@@ -54,16 +85,25 @@ func NewTestCase(opts ...func(*TestConfig)) *TestCase {
 	}
 
 	return &TestCase{
-		Config:          cfg,
-		refreshDatabase: cfg.RefreshDatabase,
+		Config:               cfg,
+		refreshDatabase:      cfg.RefreshDatabase,
+		databaseTransactions: cfg.DatabaseTransactions,
 	}
 }
 
 func (tc *TestCase) SetupTest() {
 	tc.ensureProjectRoot()
 	tc.loadEnvironment()
-	tc.handleDatabaseRefresh()
+	tc.resetHTTPState()
 	tc.bootstrapApplication()
+	if tc.Config.InMemoryDatabase {
+		if err := tc.restoreMemoryDatabase(); err != nil {
+			log.Panicf("Failed to prepare the in-memory database: %v", err)
+		}
+	} else {
+		tc.handleDatabaseRefresh()
+	}
+	tc.beginDatabaseTransaction()
 
 	if tc.Config.CustomBootstrap != nil {
 		tc.Config.CustomBootstrap(tc)
@@ -177,8 +217,13 @@ func (tc *TestCase) handleDatabaseRefresh() {
 func (tc *TestCase) bootstrapApplication() {
 	cfg := bootstrap.DefaultConfig()
 
-	if tc.Config.SetupRoutes != nil {
-		cfg.SetupRoutes = tc.Config.SetupRoutes
+	setupRoutes := tc.Config.SetupRoutes
+	cfg.SetupRoutes = func(app *fiber.App) {
+		// Runs before the application middlewares so ActingAs reaches them
+		app.Use(tc.actingAsMiddleware)
+		if setupRoutes != nil {
+			setupRoutes(app)
+		}
 	}
 
 	if tc.Config.GormConfig != nil {
@@ -193,6 +238,12 @@ func (tc *TestCase) bootstrapApplication() {
 		cfg.ConfigPath = tc.Config.ConfigPath
 	}
 
+	cfg.ConfigOverrides = map[string]any{}
+	if tc.Config.InMemoryDatabase {
+		maps.Copy(cfg.ConfigOverrides, memoryDatabaseConfig())
+	}
+	maps.Copy(cfg.ConfigOverrides, tc.Config.ConfigOverrides)
+
 	cfg.StartBackgroundJobs = false
 	cfg.IsConsoleMode = true
 
@@ -204,17 +255,115 @@ func (tc *TestCase) bootstrapApplication() {
 	tc.DB = database.Connect
 }
 
+// memoryDatabaseConfig points the default connection at a shared in-memory SQLite
+// database. A single pooled connection keeps it alive and avoids shared cache locks
+func memoryDatabaseConfig() map[string]any {
+	connection := "database.connections." + memoryConnection
+	return map[string]any{
+		"database.default":         memoryConnection,
+		connection + ".driver":     "sqlite",
+		connection + ".database":   "file::memory:?cache=shared",
+		connection + ".pool_size":  1,
+		"database.connect_retries": 0,
+	}
+}
+
+// restoreMemoryDatabase loads the migrated schema into the in-memory database.
+// The first test of the package runs the registered migrations and snapshots the
+// result, later tests restore the snapshot
+func (tc *TestCase) restoreMemoryDatabase() error {
+	memorySnapshotMu.Lock()
+	defer memorySnapshotMu.Unlock()
+
+	if memorySnapshot != nil {
+		return database.NewDumper().Restore(bytes.NewReader(memorySnapshot))
+	}
+
+	if err := database.NewMigrator().Up(); err != nil {
+		return err
+	}
+
+	// The migration lock only matters while migrating, restoring it would leave
+	// a stale lock row behind
+	var snapshot bytes.Buffer
+	if err := database.NewDumper().Dump(&snapshot, database.DumpOptions{Exclude: []string{"migrations_lock"}}); err != nil {
+		return err
+	}
+	memorySnapshot = snapshot.Bytes()
+
+	return nil
+}
+
 func (tc *TestCase) EnableRefreshDatabase() {
 	tc.refreshDatabase = true
 }
 
+// RefreshDatabase rolls back the ran migrations and runs the registered ones
+// again in process. The test package must import the application's migrations
+// package so they are registered
 func (tc *TestCase) RefreshDatabase() error {
-	cmd := exec.Command("go", "run", "main.go", "console", "db:fresh", "--force")
-	cmd.Dir = tc.projectRoot
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	if len(database.DefaultRegistry.GetMigrations()) == 0 {
+		return errors.New("no migrations are registered, import the application's migrations package in the test package")
+	}
+	if database.Connect == nil {
+		return errors.New("database is not connected")
+	}
 
-	return cmd.Run()
+	return database.NewMigrator().Refresh()
+}
+
+// EnableDatabaseTransactions wraps each test in a transaction rolled back in TearDownTest
+func (tc *TestCase) EnableDatabaseTransactions() {
+	tc.databaseTransactions = true
+}
+
+// beginDatabaseTransaction swaps database.Connect for a transaction, so
+// everything the test and the application write is rolled back afterwards
+func (tc *TestCase) beginDatabaseTransaction() {
+	if !tc.databaseTransactions && !tc.Config.DatabaseTransactions {
+		return
+	}
+	if database.Connect == nil {
+		log.Printf("Warning: Database is not connected, running without a test transaction")
+		return
+	}
+
+	tc.connection = database.Connect
+
+	tx := tc.connection.Begin()
+	if tx.Error != nil {
+		log.Panicf("Failed to begin test transaction: %v", tx.Error)
+	}
+	if err := tx.SavePoint(testSavePoint).Error; err != nil {
+		tx.Rollback()
+		log.Panicf("Failed to create test savepoint: %v", err)
+	}
+
+	database.Connect = tx
+	tc.DB = tx
+}
+
+// ResetTransaction rolls the test transaction back to the start of the test, for
+// instance to keep querying after an expected error aborted it on PostgreSQL
+func (tc *TestCase) ResetTransaction() error {
+	if tc.connection == nil {
+		return errors.New("test is not running in a database transaction")
+	}
+
+	return tc.DB.RollbackTo(testSavePoint).Error
+}
+
+// rollbackDatabaseTransaction discards the test transaction and restores database.Connect
+func (tc *TestCase) rollbackDatabaseTransaction() {
+	if tc.connection == nil {
+		return
+	}
+
+	tc.DB.Rollback()
+
+	database.Connect = tc.connection
+	tc.DB = tc.connection
+	tc.connection = nil
 }
 
 func (tc *TestCase) RefreshDatabaseBetweenTests() {
@@ -222,6 +371,8 @@ func (tc *TestCase) RefreshDatabaseBetweenTests() {
 }
 
 func (tc *TestCase) TearDownTest() {
+	tc.rollbackDatabaseTransaction()
+
 	if tc.DB != nil {
 		sqlDB, err := tc.DB.DB()
 		if err == nil {